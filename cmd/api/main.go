@@ -1,49 +1,115 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/health"
 	"github.com/alonsoF100/golos/internal/logger"
-	"github.com/alonsoF100/golos/internal/repository/database/postgres"
+	"github.com/alonsoF100/golos/internal/repository/cache/redis"
 	"github.com/alonsoF100/golos/internal/service"
+	"github.com/alonsoF100/golos/internal/tracing"
 	"github.com/alonsoF100/golos/internal/transport/http/handlers"
 	"github.com/alonsoF100/golos/internal/transport/http/router"
-	_ "github.com/alonsoF100/golos/migrations/postgres"
 )
 
 func main() {
-	// Инициализация конфига
-	config := config.Load()
+	// Инициализация конфига: defaults -> yaml -> env -> флаги
+	var configFlags config.Flags
+	configFlags.Register(flag.CommandLine)
+	flag.Parse()
+
+	config, err := config.Load(configFlags)
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		os.Exit(1)
+	}
 
 	// Создание looger-а
 	logger.Setup(config)
+	slog.Debug("Config loaded", "config", config)
+
+	// Трассировка до pool-а, чтобы tracer pgx писал в настроенный provider
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		slog.Error("Failed to setup tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
 
-	// Создание pool-а
-	pool, err := postgres.NewPool(config)
+	// Зависимости для /readyz
+	checker := health.New()
+
+	// Создание слоя repo: postgres или память
+	dataBase, closeStorage, err := openStorage(context.Background(), config, checker)
 	if err != nil {
-		slog.Error("Failed to create pool", "error", err)
+		slog.Error("Failed to open storage", "driver", config.Database.Driver, "error", err)
+		os.Exit(1)
 	}
-	defer pool.Close()
-	slog.Info("Pool created successfully")
+	defer closeStorage()
+
+	// Счетчики входов и лимиты в redis, если он настроен, иначе в основном хранилище и памяти
+	var loginAttempts service.LoginAttemptRepository = dataBase
+	var rateLimitStore router.RateLimitStore = router.NewMemoryRateLimitStore()
+	if config.Redis.Enabled() {
+		redisClient, err := redis.NewClient(config)
+		if err != nil {
+			slog.Error("Failed to connect to redis", "error", err)
+			os.Exit(1)
+		}
+		defer redisClient.Close()
+		slog.Info("Redis connected successfully")
 
-	// Создание слоя repo
-	dataBase := postgres.New(pool)
+		cache := redis.New(redisClient)
+		checker.Register("redis", cache.Ping)
+		loginAttempts = cache
+		rateLimitStore = cache
+	}
 
 	// Создание слоя service
 	svc := service.New(
-		dataBase, // user repo
-		dataBase, // election repo
-		dataBase, // voteVariat repo
-		dataBase, // vote repo
+		dataBase,      // user repo
+		dataBase,      // election repo
+		dataBase,      // voteVariat repo
+		dataBase,      // vote repo
+		dataBase,      // role repo
+		dataBase,      // login lockout repo
+		dataBase,      // idempotency repo
+		dataBase,      // audit repo
+		dataBase,      // translation repo
+		loginAttempts, // login attempt repo
+		config,
 	)
 
+	// Периодическая очистка истекших ключей идемпотентности
+	if config.Idempotency.PurgeInterval > 0 {
+		go purgeIdempotencyKeys(svc, config.Idempotency.PurgeInterval)
+	}
+
+	// Окончательное удаление мягко удаленных записей после retention
+	if config.SoftDelete.PurgeInterval > 0 {
+		go purgeDeleted(svc, config.SoftDelete.PurgeInterval)
+	}
+
 	// Создание слоя http
-	handler := handlers.New(svc)
+	handler := handlers.New(svc, checker)
 
 	// Сетап router-а
-	router := router.New(handler).Setup()
+	limiter := router.NewRateLimiter(rateLimitStore, config.RateLimit)
+	router := router.New(handler, limiter, config.Metrics).Setup()
 
 	// Сетап сервера // TODO потом отдельный файл сделать с сетапом
 	server := &http.Server{
@@ -55,8 +121,64 @@ func main() {
 	}
 
 	// Запуск сервера
-	slog.Info("Starting server", "port", config.Server.Port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		slog.Error("Server failed", "error", err)
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting server", "port", config.Server.Port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		// Занятый порт и другие ошибки запуска - ненулевой код, иначе оркестратор сочтет выход штатным
+		if err != nil && err != http.ErrServerClosed {
+			slog.Error("Server failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	case sig := <-stop:
+		slog.Info("Shutting down", "signal", sig.String())
+	}
+
+	// Сначала /readyz уходит в not ready, чтобы балансировщик успел снять трафик
+	checker.SetShuttingDown()
+	time.Sleep(config.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Graceful shutdown failed", "error", err)
+	}
+	slog.Info("Server stopped")
+}
+
+func purgeIdempotencyKeys(svc *service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		purged, err := svc.PurgeExpiredIdempotencyKeys(context.Background())
+		if err != nil {
+			slog.Error("Failed to purge idempotency keys", "error", err)
+			continue
+		}
+		slog.Debug("Idempotency keys purged", "count", purged)
+	}
+}
+
+func purgeDeleted(svc *service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		result, err := svc.PurgeExpiredDeleted(context.Background())
+		if err != nil {
+			slog.Error("Failed to purge deleted records", "error", err)
+			continue
+		}
+		slog.Info("Deleted records purged",
+			"users", result.Users,
+			"elections", result.Elections,
+			"vote_variants", result.VoteVariants)
 	}
 }
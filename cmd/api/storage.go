@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/health"
+	"github.com/alonsoF100/golos/internal/metrics"
+	"github.com/alonsoF100/golos/internal/repository/database/memory"
+	"github.com/alonsoF100/golos/internal/repository/database/postgres"
+	"github.com/alonsoF100/golos/internal/repository/database/sqlite"
+	"github.com/alonsoF100/golos/internal/service"
+)
+
+// Хранилище по database.driver, его проверки готовности регистрируются в checker
+func openStorage(ctx context.Context, cfg *config.Config, checker *health.Checker) (service.Storage, func(), error) {
+	switch cfg.Database.Driver {
+	case config.DriverMemory:
+		slog.Warn("Using in-memory storage, data is lost on restart")
+		return memory.New(), func() {}, nil
+	case config.DriverSQLite:
+		return openSQLite(ctx, cfg, checker)
+	default:
+		return openPostgres(ctx, cfg, checker)
+	}
+}
+
+func openPostgres(ctx context.Context, cfg *config.Config, checker *health.Checker) (service.Storage, func(), error) {
+	// Создание pool-а
+	pool, err := postgres.NewPool(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create pool: %w", err)
+	}
+	slog.Info("Pool created successfully")
+
+	// Статистика пула для /metrics
+	if cfg.Metrics.Enabled {
+		if err := metrics.RegisterPool(pool); err != nil {
+			slog.Error("Failed to register pool metrics", "error", err)
+		}
+	}
+
+	// Миграции под advisory lock, в проде выключаются и запускаются через cmd/migrate
+	if cfg.Migration.Auto {
+		if err := postgres.Migrate(ctx, pool); err != nil {
+			pool.Close()
+			return nil, nil, fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	// Реплики для списков и итогов, при недоступности запросы идут в primary
+	replicas, err := postgres.NewReplicaPools(ctx, cfg)
+	if err != nil {
+		pool.Close()
+		return nil, nil, fmt.Errorf("create replica pools: %w", err)
+	}
+	if len(replicas) > 0 {
+		slog.Info("Replica pools created", "count", len(replicas))
+	}
+	cleanup := func() {
+		for _, replica := range replicas {
+			replica.Close()
+		}
+		pool.Close()
+	}
+
+	dataBase := postgres.New(pool, replicas...).WithReplicaCooldown(cfg.Database.ReplicaCooldown)
+
+	latestMigration, err := postgres.LatestMigration(pool)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("collect migrations: %w", err)
+	}
+	checker.Register("postgres", dataBase.Ping)
+	checker.Register("migrations", func(ctx context.Context) error {
+		return dataBase.CheckMigrations(ctx, latestMigration)
+	})
+
+	return dataBase, cleanup, nil
+}
+
+func openSQLite(ctx context.Context, cfg *config.Config, checker *health.Checker) (service.Storage, func(), error) {
+	db, err := sqlite.Open(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	slog.Info("SQLite database opened", "path", cfg.Database.Path)
+	cleanup := func() { db.Close() }
+
+	if cfg.Migration.Auto {
+		if err := sqlite.Migrate(ctx, db); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	dataBase := sqlite.New(db)
+
+	latestMigration, err := sqlite.LatestMigration(db)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("collect migrations: %w", err)
+	}
+	checker.Register("sqlite", dataBase.Ping)
+	checker.Register("migrations", func(ctx context.Context) error {
+		return dataBase.CheckMigrations(ctx, latestMigration)
+	})
+
+	return dataBase, cleanup, nil
+}
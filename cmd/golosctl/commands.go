@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+	"golang.org/x/term"
+)
+
+const (
+	minPasswordLength = 5
+	maxPasswordLength = 20
+)
+
+func (a app) run(ctx context.Context, args []string) error {
+	command, args := args[0], args[1:]
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch {
+	case command == "user" && sub == "create":
+		return a.createUser(ctx, args[1:])
+	case command == "user" && sub == "reset-password":
+		return a.resetPassword(ctx, args[1:])
+	case command == "election" && sub == "list":
+		return a.listElections(ctx, args[1:])
+	case command == "election" && sub == "open":
+		return a.setElectionStatus(ctx, args[1:], models.ElectionStatusOpen)
+	case command == "election" && sub == "close":
+		return a.setElectionStatus(ctx, args[1:], models.ElectionStatusClosed)
+	case command == "variant" && sub == "add":
+		return a.addVariant(ctx, args[1:])
+	case command == "results":
+		return a.results(ctx, args)
+	case command == "ballots" && sub == "export":
+		return a.exportBallots(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, see golosctl -h", strings.TrimSpace(command+" "+sub))
+	}
+}
+
+func (a app) createUser(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user create", flag.ExitOnError)
+	nickname := flags.String("nickname", "", "nickname")
+	password := flags.String("password", "", "password (read from stdin when empty)")
+	role := flags.String("role", models.RoleUser, "role: user, moderator or admin")
+	flags.Parse(args)
+
+	if *nickname == "" {
+		return errors.New("-nickname is required")
+	}
+	if *role != models.RoleUser && *role != models.RoleModerator && *role != models.RoleAdmin {
+		return fmt.Errorf("unknown role %q", *role)
+	}
+	pass, err := readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.svc.CreateUser(ctx, requestMeta(), *nickname, pass)
+	if err != nil {
+		return err
+	}
+	user.Role = models.RoleUser
+	if *role != models.RoleUser {
+		// Пользователь уже создан: без его ID повтор команды упрется в занятый nickname
+		updated, err := a.svc.SetUserRole(ctx, requestMeta(), user.ID, *role)
+		if err != nil {
+			return fmt.Errorf("user %s created with role %s, but setting role %s failed: %w", user.ID, models.RoleUser, *role, err)
+		}
+		user = updated
+	}
+
+	return a.out.users(user)
+}
+
+func (a app) resetPassword(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+	id := flags.String("id", "", "user id")
+	password := flags.String("password", "", "new password (read from stdin when empty)")
+	flags.Parse(args)
+
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+	pass, err := readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	if err := a.svc.ResetPassword(ctx, requestMeta(), *id, pass); err != nil {
+		return err
+	}
+
+	return a.out.message("password reset for user " + *id)
+}
+
+func (a app) listElections(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("election list", flag.ExitOnError)
+	status := flags.String("status", "", "filter by status: open or closed")
+	query := flags.String("query", "", "search in name and description")
+	limit := flags.Int("limit", 20, "page size (max 100)")
+	offset := flags.Int("offset", 0, "page offset")
+	flags.Parse(args)
+
+	filter := models.ElectionFilter{
+		Status: *status,
+		Query:  *query,
+	}
+	// Без локали выводятся исходные названия, переводы не подставляются
+	elections, err := a.svc.SearchElections(ctx, *limit, *offset, filter, "")
+	if err != nil {
+		return err
+	}
+
+	return a.out.elections(elections...)
+}
+
+func (a app) setElectionStatus(ctx context.Context, args []string, status string) error {
+	if len(args) != 1 {
+		return errors.New("election id is required")
+	}
+
+	var (
+		election *models.Election
+		err      error
+	)
+	// Закрытие идет через админский метод, чтобы в журнале было действие close
+	if status == models.ElectionStatusClosed {
+		election, err = a.svc.CloseElection(ctx, requestMeta(), args[0])
+	} else {
+		election, err = a.svc.PatchElection(ctx, requestMeta(), args[0], nil, nil, nil, &status, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.out.elections(election)
+}
+
+func (a app) addVariant(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("variant add", flag.ExitOnError)
+	electionID := flags.String("election", "", "election id")
+	name := flags.String("name", "", "variant name")
+	flags.Parse(args)
+
+	if *electionID == "" || *name == "" {
+		return errors.New("-election and -name are required")
+	}
+
+	variant, err := a.svc.CreateVoteVariant(ctx, requestMeta(), *electionID, *name)
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(dto.NewVoteVariantResponse(variant), func(t *table) {
+		t.row("ID", "ELECTION", "NAME")
+		t.row(variant.ID, variant.ElectionID, variant.Name)
+	})
+}
+
+func (a app) results(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("election id is required")
+	}
+
+	results, err := a.svc.GetElectionResults(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return a.out.results(results)
+}
+
+func (a app) exportBallots(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("election id is required")
+	}
+
+	votes, err := a.svc.ExportBallots(ctx, operator, args[0])
+	if err != nil {
+		return err
+	}
+
+	return a.out.ballots(votes)
+}
+
+// Пароль из флага попадает в историю shell, поэтому по умолчанию он читается из stdin:
+// с терминала без эха, из пайпа - первой строкой
+func readPassword(password string) (string, error) {
+	if password == "" {
+		fmt.Fprint(os.Stderr, "password: ")
+		if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+			input, err := term.ReadPassword(fd)
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return "", fmt.Errorf("read password: %w", err)
+			}
+			password = string(input)
+		} else {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				return "", fmt.Errorf("read password: %w", err)
+			}
+			password = strings.TrimRight(line, "\r\n")
+		}
+	}
+
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", fmt.Errorf("password must be %d to %d characters", minPasswordLength, maxPasswordLength)
+	}
+
+	return password, nil
+}
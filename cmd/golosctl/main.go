@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/repository/cache/redis"
+	"github.com/alonsoF100/golos/internal/repository/database/postgres"
+	"github.com/alonsoF100/golos/internal/repository/database/sqlite"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+const usage = `Usage: golosctl [-config path] [-set key=value] [-o table|json] <command> [args]
+
+Commands:
+  user create -nickname NAME [-password PASS] [-role user|moderator|admin]
+  user reset-password -id USER_ID [-password PASS]
+  election list [-status open|closed] [-query TEXT] [-limit N] [-offset N]
+  election open ELECTION_ID
+  election close ELECTION_ID
+  variant add -election ELECTION_ID -name NAME
+  results ELECTION_ID
+  ballots export ELECTION_ID
+
+Without -password the password is read from stdin.
+`
+
+// Оператор CLI действует как администратор без учетной записи, в журнале actor_id пустой
+var operator = &models.User{Nickname: "golosctl", Role: models.RoleAdmin}
+
+type app struct {
+	svc *service.Service
+	out *printer
+}
+
+func main() {
+	flags := flag.NewFlagSet("golosctl", flag.ExitOnError)
+	format := flags.String("o", formatTable, "output format: table or json")
+	var configFlags config.Flags
+	configFlags.Register(flags)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	out, err := newPrinter(os.Stdout, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "golosctl:", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load(configFlags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "golosctl:", err)
+		os.Exit(1)
+	}
+
+	svc, cleanup, err := newService(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "golosctl:", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	a := app{svc: svc, out: out}
+	if err := a.run(ctx, flags.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "golosctl:", err)
+		stop()
+		cleanup()
+		os.Exit(1)
+	}
+}
+
+// Та же сборка слоев, что и в cmd/api, но без http
+func newService(ctx context.Context, cfg *config.Config) (*service.Service, func(), error) {
+	var (
+		dataBase service.Storage
+		cleanup  func()
+	)
+	switch cfg.Database.Driver {
+	case config.DriverPostgres:
+		pool, err := postgres.NewPool(ctx, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect to postgres: %w", err)
+		}
+		dataBase = postgres.New(pool)
+		cleanup = pool.Close
+	case config.DriverSQLite:
+		db, err := sqlite.Open(ctx, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open sqlite: %w", err)
+		}
+		dataBase = sqlite.New(db)
+		cleanup = func() { db.Close() }
+	default:
+		// Данные в памяти живут только внутри процесса API, CLI их не увидит
+		return nil, nil, fmt.Errorf("database.driver %q is not supported by golosctl", cfg.Database.Driver)
+	}
+
+	var loginAttempts service.LoginAttemptRepository = dataBase
+	if cfg.Redis.Enabled() {
+		redisClient, err := redis.NewClient(cfg)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("connect to redis: %w", err)
+		}
+		loginAttempts = redis.New(redisClient)
+		closeDataBase := cleanup
+		cleanup = func() {
+			redisClient.Close()
+			closeDataBase()
+		}
+	}
+
+	svc := service.New(
+		dataBase,      // user repo
+		dataBase,      // election repo
+		dataBase,      // voteVariat repo
+		dataBase,      // vote repo
+		dataBase,      // role repo
+		dataBase,      // login lockout repo
+		dataBase,      // idempotency repo
+		dataBase,      // audit repo
+		dataBase,      // translation repo
+		loginAttempts, // login attempt repo
+		cfg,
+	)
+
+	return svc, cleanup, nil
+}
+
+// У каждого вызова свой request_id, чтобы действия CLI различались в журнале
+func requestMeta() models.RequestMeta {
+	return models.RequestMeta{
+		Actor:     operator,
+		RequestID: "golosctl-" + uuid.New().String(),
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != formatTable && format != formatJSON {
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+
+	return &printer{w: w, format: format}, nil
+}
+
+type table struct {
+	w *tabwriter.Writer
+}
+
+func (t *table) row(cells ...string) {
+	fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+}
+
+// JSON повторяет формат ответов API, таблица - для чтения глазами
+func (p *printer) print(data any, fill func(t *table)) error {
+	if p.format == formatJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+
+	t := &table{w: tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)}
+	fill(t)
+
+	return t.w.Flush()
+}
+
+func (p *printer) message(text string) error {
+	return p.print(map[string]string{"message": text}, func(t *table) {
+		t.row(text)
+	})
+}
+
+func (p *printer) users(users ...*models.User) error {
+	data := make([]dto.UserSelfResponse, 0, len(users))
+	for _, user := range users {
+		data = append(data, dto.NewUserSelfResponse(user))
+	}
+
+	return p.print(data, func(t *table) {
+		t.row("ID", "NICKNAME", "ROLE", "CREATED AT")
+		for _, user := range users {
+			t.row(user.ID, user.Nickname, user.Role, user.CreatedAt.Format(time.RFC3339))
+		}
+	})
+}
+
+func (p *printer) elections(elections ...*models.Election) error {
+	data := make([]dto.ElectionResponse, 0, len(elections))
+	for _, election := range elections {
+		data = append(data, dto.NewElectionResponse(election))
+	}
+
+	return p.print(data, func(t *table) {
+		t.row("ID", "NAME", "STATUS", "POLICY", "CREATED AT")
+		for _, election := range elections {
+			t.row(election.ID, election.Name, election.Status, election.VotePolicy, election.CreatedAt.Format(time.RFC3339))
+		}
+	})
+}
+
+type variantResult struct {
+	VariantID string  `json:"variant_id"`
+	Name      string  `json:"name"`
+	Votes     int64   `json:"votes"`
+	Share     float64 `json:"share"`
+}
+
+func (p *printer) results(results []*models.VariantResult) error {
+	var total int64
+	for _, result := range results {
+		total += result.Votes
+	}
+
+	data := make([]variantResult, 0, len(results))
+	for _, result := range results {
+		var share float64
+		if total > 0 {
+			share = float64(result.Votes) / float64(total)
+		}
+		data = append(data, variantResult{
+			VariantID: result.VariantID,
+			Name:      result.Name,
+			Votes:     result.Votes,
+			Share:     share,
+		})
+	}
+
+	return p.print(data, func(t *table) {
+		t.row("VARIANT", "NAME", "VOTES", "SHARE")
+		for _, result := range data {
+			t.row(result.VariantID, result.Name, fmt.Sprint(result.Votes), fmt.Sprintf("%.1f%%", result.Share*100))
+		}
+		t.row("", "TOTAL", fmt.Sprint(total), "")
+	})
+}
+
+func (p *printer) ballots(votes []*models.Vote) error {
+	data := make([]dto.VoteResponse, 0, len(votes))
+	for _, vote := range votes {
+		data = append(data, dto.NewVoteResponse(vote))
+	}
+
+	return p.print(data, func(t *table) {
+		t.row("VOTE", "USER", "VARIANT", "CAST AT", "UPDATED AT")
+		for _, vote := range votes {
+			t.row(vote.ID, vote.UserID, vote.VariantID, vote.CreatedAt.Format(time.RFC3339), vote.UpdatedAt.Format(time.RFC3339))
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	migrationFile = regexp.MustCompile(`^(\d+)_\w+\.go$`)
+	migrationName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+var migrationTemplate = template.Must(template.New("migration").Parse(`package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(up{{.Func}}, down{{.Func}})
+}
+
+func up{{.Func}}(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, ` + "``" + `)
+	return err
+}
+
+func down{{.Func}}(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, ` + "``" + `)
+	return err
+}
+`))
+
+// Номер следующей миграции по файлам в dir, имя файла в стиле 0001_users.go
+func createMigration(dir, name string) (string, error) {
+	if !migrationName.MatchString(name) {
+		return "", fmt.Errorf("migration name %q must be snake_case", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return "", err
+		}
+		latest = max(latest, version)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%04d_%s.go", latest+1, name))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return path, migrationTemplate.Execute(file, struct{ Func string }{Func: camelCase(name)})
+}
+
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
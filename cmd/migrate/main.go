@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/repository/database/postgres"
+	"github.com/alonsoF100/golos/internal/repository/database/sqlite"
+	"github.com/pressly/goose/v3"
+)
+
+const usage = `Usage: migrate [-config path] [-set key=value] [-dir path] <command> [args]
+
+Commands:
+  up            apply all pending migrations
+  down          roll back the latest migration
+  redo          roll back the latest migration and apply it again
+  status        list migrations and their state
+  version       print the current schema version
+  create NAME   create a new Go migration in -dir
+`
+
+func main() {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := flags.String("dir", "", "directory for new migrations (default: migrations.dir from config)")
+	var configFlags config.Flags
+	configFlags.Register(flags)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, configFlags, args[0], args[1:], *dir); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configFlags config.Flags, command string, args []string, dir string) error {
+	cfg, err := config.Load(configFlags)
+	if err != nil {
+		return err
+	}
+	if dir == "" {
+		dir = cfg.Migration.Dir
+	}
+
+	// Создание файла не требует подключения к базе
+	if command == "create" {
+		if len(args) != 1 {
+			return errors.New("create requires a migration name")
+		}
+		path, err := createMigration(dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println("created", path)
+		return nil
+	}
+
+	migrator, cleanup, err := openMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	switch command {
+	case "up":
+		results, err := migrator.Up(ctx)
+		printResults(results)
+		return err
+	case "down":
+		result, err := migrator.Down(ctx)
+		printResults([]*goose.MigrationResult{result})
+		return err
+	case "redo":
+		result, err := migrator.Down(ctx)
+		printResults([]*goose.MigrationResult{result})
+		if err != nil {
+			return err
+		}
+		result, err = migrator.UpByOne(ctx)
+		printResults([]*goose.MigrationResult{result})
+		return err
+	case "status":
+		return printStatus(ctx, migrator)
+	case "version":
+		version, err := migrator.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// Мигратор для database.driver, у memory схемы нет
+func openMigrator(ctx context.Context, cfg *config.Config) (*goose.Provider, func(), error) {
+	switch cfg.Database.Driver {
+	case config.DriverPostgres:
+		pool, err := postgres.NewPool(ctx, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect: %w", err)
+		}
+
+		migrator, err := postgres.NewMigrator(pool)
+		if err != nil {
+			pool.Close()
+			return nil, nil, err
+		}
+
+		return migrator, func() {
+			migrator.Close()
+			pool.Close()
+		}, nil
+	case config.DriverSQLite:
+		db, err := sqlite.Open(ctx, cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open: %w", err)
+		}
+
+		migrator, err := sqlite.NewMigrator(db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+
+		// Close провайдера закрывает и db
+		return migrator, func() { migrator.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("database.driver %q has no migrations", cfg.Database.Driver)
+	}
+}
+
+func printResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		fmt.Printf("%-4s %05d %s (%s)\n", result.Direction, result.Source.Version, result.Source.Path, result.Duration.Round(time.Millisecond))
+	}
+}
+
+func printStatus(ctx context.Context, migrator *goose.Provider) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+	}
+
+	return w.Flush()
+}
@@ -5,26 +5,55 @@ import (
 )
 
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Logger    LoggerConfig    `mapstructure:"logger"`
-	Migration MigrationConfig `mapstructure:"migrations"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Logger      LoggerConfig      `mapstructure:"logger"`
+	Migration   MigrationConfig   `mapstructure:"migrations"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	SoftDelete  SoftDeleteConfig  `mapstructure:"soft_delete"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Tracing     TracingConfig     `mapstructure:"tracing"`
 }
 
+// shutdown_delay - сколько /readyz отвечает not ready до остановки приема запросов
 type ServerConfig struct {
-	Port         int           `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	Port            int           `mapstructure:"port"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
+// Нулевые настройки пула оставляют значения pgx по умолчанию.
+// driver - хранилище: postgres, sqlite (один узел, файл path) или memory (демо и тесты, данные теряются при перезапуске).
+// replicas - DSN реплик для списков и итогов, пустой список - все запросы в primary.
+// connect_attempts - попытки подключения при старте, пауза удваивается от connect_backoff до connect_max_backoff
 type DatabaseConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     string `mapstructure:"port"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Name     string `mapstructure:"name"`
-	SSlMode  string `mapstructure:"ssl_mode"`
+	Driver                string        `mapstructure:"driver"`
+	Path                  string        `mapstructure:"path"`
+	Host                  string        `mapstructure:"host"`
+	Port                  string        `mapstructure:"port"`
+	User                  string        `mapstructure:"user"`
+	Password              Secret        `mapstructure:"password"`
+	Name                  string        `mapstructure:"name"`
+	SSlMode               string        `mapstructure:"ssl_mode"`
+	ApplicationName       string        `mapstructure:"application_name"`
+	StatementTimeout      time.Duration `mapstructure:"statement_timeout"`
+	MaxConns              int32         `mapstructure:"max_conns"`
+	MinConns              int32         `mapstructure:"min_conns"`
+	MaxConnLifetime       time.Duration `mapstructure:"max_conn_lifetime"`
+	MaxConnIdleTime       time.Duration `mapstructure:"max_conn_idle_time"`
+	HealthCheckPeriod     time.Duration `mapstructure:"health_check_period"`
+	ConnectAttempts       int           `mapstructure:"connect_attempts"`
+	ConnectBackoff        time.Duration `mapstructure:"connect_backoff"`
+	ConnectMaxBackoff     time.Duration `mapstructure:"connect_max_backoff"`
+	Replicas              []Secret      `mapstructure:"replicas"`
+	ReplicaConnectTimeout time.Duration `mapstructure:"replica_connect_timeout"`
+	ReplicaCooldown       time.Duration `mapstructure:"replica_cooldown"`
 }
 
 type LoggerConfig struct {
@@ -32,6 +61,74 @@ type LoggerConfig struct {
 	JSON  bool   `mapstructure:"json"`
 }
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// auto - API накатывает миграции при старте, иначе только через cmd/migrate.
+// dir нужен cmd/migrate create для новых файлов
 type MigrationConfig struct {
-	Dir string `mapstructure:"dir"`
+	Dir  string `mapstructure:"dir"`
+	Auto bool   `mapstructure:"auto"`
+}
+
+// Защита от перебора паролей, нулевой порог отключает блокировку
+type AuthConfig struct {
+	MaxFailedAttempts      int           `mapstructure:"max_failed_attempts"`
+	MaxFailedAttemptsPerIP int           `mapstructure:"max_failed_attempts_per_ip"`
+	FailureWindow          time.Duration `mapstructure:"failure_window"`
+	BaseLockout            time.Duration `mapstructure:"base_lockout"`
+	MaxLockout             time.Duration `mapstructure:"max_lockout"`
+}
+
+// Redis необязателен, без host используется postgres
+type RedisConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Password Secret `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+// Бюджеты запросов по группам маршрутов.
+// credentials - запросы с Basic-учетными данными по ip, проверяется до bcrypt
+type RateLimitConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Credentials RateLimitRule `mapstructure:"credentials"`
+	Auth        RateLimitRule `mapstructure:"auth"`
+	Voting      RateLimitRule `mapstructure:"voting"`
+	Writes      RateLimitRule `mapstructure:"writes"`
+	Reads       RateLimitRule `mapstructure:"reads"`
+}
+
+// Token bucket: rate токенов в секунду, не больше burst, rate <= 0 снимает ограничение
+type RateLimitRule struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type IdempotencyConfig struct {
+	TTL           time.Duration `mapstructure:"ttl"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// Мягко удаленные записи удаляются окончательно спустя retention
+type SoftDeleteConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// Prometheus /metrics, выключается если scrape не нужен
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// OpenTelemetry, exporter: otlp (http) или stdout для локальной отладки
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
 }
@@ -0,0 +1,73 @@
+package config
+
+import "time"
+
+// Нижний слой конфигурации: поверх него применяются yaml, env и флаги
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     5 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     10 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:                DriverPostgres,
+			Path:                  "golos.db",
+			Host:                  "localhost",
+			Port:                  "5432",
+			User:                  "postgres",
+			Name:                  "golos",
+			SSlMode:               "disable",
+			ApplicationName:       "golos",
+			ConnectAttempts:       5,
+			ConnectBackoff:        time.Second,
+			ConnectMaxBackoff:     10 * time.Second,
+			ReplicaConnectTimeout: 2 * time.Second,
+			ReplicaCooldown:       30 * time.Second,
+		},
+		Logger: LoggerConfig{
+			Level: "info",
+		},
+		Migration: MigrationConfig{
+			Dir:  "migrations/postgres",
+			Auto: true,
+		},
+		Auth: AuthConfig{
+			MaxFailedAttempts:      5,
+			MaxFailedAttemptsPerIP: 20,
+			FailureWindow:          15 * time.Minute,
+			BaseLockout:            time.Minute,
+			MaxLockout:             time.Hour,
+		},
+		Redis: RedisConfig{
+			Port: "6379",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     true,
+			Credentials: RateLimitRule{Rate: 5, Burst: 20},
+			Auth:        RateLimitRule{Rate: 0.2, Burst: 5},
+			Voting:      RateLimitRule{Rate: 2, Burst: 10},
+			Writes:      RateLimitRule{Rate: 1, Burst: 20},
+			Reads:       RateLimitRule{Rate: 10, Burst: 50},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:           24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		SoftDelete: SoftDeleteConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: 24 * time.Hour,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "otlp",
+			ServiceName: "golos",
+			SampleRatio: 1,
+		},
+	}
+}
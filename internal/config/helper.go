@@ -1,19 +1,30 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
+// url.URL экранирует спецсимволы в пароле
 func (cfg *DatabaseConfig) ConStr() string {
-	return fmt.Sprintf(
-		"postgresql://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Name,
-		cfg.SSlMode,
-	)
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(cfg.User, cfg.Password.Value()),
+		Host:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Path:     cfg.Name,
+		RawQuery: url.Values{"sslmode": {cfg.SSlMode}}.Encode(),
+	}
+	return u.String()
 }
 
 func (cfg *ServerConfig) PortStr() string {
 	return fmt.Sprintf(":%d", cfg.Port)
 }
+
+func (cfg *RedisConfig) Enabled() bool {
+	return cfg.Host != ""
+}
+
+func (cfg *RedisConfig) Addr() string {
+	return fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+}
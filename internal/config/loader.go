@@ -1,36 +1,160 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
-func Load() *Config {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal(".env file not found", err)
+const (
+	envPrefix   = "GOLOS"
+	defaultPath = "config.yaml"
+)
+
+// Прежние имена переменных из docker-compose и .env продолжают работать
+var legacyEnv = map[string]string{
+	"database.host":     "DB_HOST",
+	"database.port":     "DB_PORT",
+	"database.user":     "DB_USER",
+	"database.password": "DB_PASSWORD",
+	"database.name":     "DB_NAME",
+	"database.ssl_mode": "DB_SSL_MODE",
+	"redis.host":        "REDIS_HOST",
+	"redis.port":        "REDIS_PORT",
+	"redis.password":    "REDIS_PASSWORD",
+}
+
+// Флаги конфигурации, общие для всех бинарников
+type Flags struct {
+	Path      string
+	Overrides overrides
+}
+
+func (f *Flags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.Path, "config", "", "path to the yaml config (default $GOLOS_CONFIG or "+defaultPath+")")
+	fs.Var(&f.Overrides, "set", "override a config key, e.g. -set server.port=9090 (repeatable)")
+}
+
+type overrides []string
+
+func (o *overrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *overrides) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected key=value, got %q", value)
 	}
+	*o = append(*o, value)
+	return nil
+}
 
-	viper.SetConfigFile("config.yaml")
+// Слои по возрастанию приоритета: Default() -> yaml -> env (GOLOS_SERVER_PORT ...) -> -set
+func Load(flags Flags) (*Config, error) {
+	// .env необязателен: в docker переменные приходят из окружения
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatal("Error reading config file:", err)
+	v := viper.New()
+	keys := setDefaults(v, reflect.ValueOf(Default()), "")
+
+	for _, key := range keys {
+		envs := []string{key, envName(key)}
+		if legacy, ok := legacyEnv[key]; ok {
+			envs = append(envs, legacy)
+		}
+		if err := v.BindEnv(envs...); err != nil {
+			return nil, err
+		}
 	}
 
-	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		log.Fatal("Unable to decode config:", err)
+	if err := readConfigFile(v, flags.Path); err != nil {
+		return nil, err
 	}
 
-	config.Database.Host = os.Getenv("DB_HOST")
-	config.Database.Port = os.Getenv("DB_PORT")
-	config.Database.User = os.Getenv("DB_USER")
-	config.Database.Password = os.Getenv("DB_PASSWORD")
-	config.Database.Name = os.Getenv("DB_NAME")
-	config.Database.SSlMode = os.Getenv("DB_SSL_MODE")
+	for _, override := range flags.Overrides {
+		key, value, _ := strings.Cut(override, "=")
+		if !contains(keys, key) {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+		v.Set(key, value)
+	}
+
+	// Неизвестные ключи в yaml - скорее опечатка, чем намерение
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
 
-	log.Println("Config loaded successfully")
-	return &config
+	return &cfg, nil
+}
+
+// Явно указанный файл обязателен, файл по умолчанию - нет
+func readConfigFile(v *viper.Viper, path string) error {
+	if path == "" {
+		path = os.Getenv(envPrefix + "_CONFIG")
+	}
+	optional := path == ""
+	if optional {
+		path = defaultPath
+	}
+
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Регистрирует значения по умолчанию и возвращает все ключи вида server.port
+func setDefaults(v *viper.Viper, value reflect.Value, prefix string) []string {
+	var keys []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := field.Tag.Get("mapstructure")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != reflect.TypeOf(time.Time{}) {
+			keys = append(keys, setDefaults(v, fieldValue, key)...)
+			continue
+		}
+
+		v.SetDefault(key, fieldValue.Interface())
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	drivers        = []string{DriverPostgres, DriverSQLite, DriverMemory}
+	sslModes       = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	logLevels      = []string{"debug", "info", "warn", "error"}
+	traceExporters = []string{"otlp", "stdout"}
+)
+
+// Все ошибки собираются вместе, чтобы не чинить конфиг по одной
+func (cfg *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(cfg.Server.Port > 0 && cfg.Server.Port <= 65535, "server.port", "must be in 1..65535, got %d", cfg.Server.Port)
+	check(cfg.Server.ReadTimeout >= 0, "server.read_timeout", "must not be negative")
+	check(cfg.Server.WriteTimeout >= 0, "server.write_timeout", "must not be negative")
+	check(cfg.Server.IdleTimeout >= 0, "server.idle_timeout", "must not be negative")
+	check(cfg.Server.ShutdownDelay >= 0, "server.shutdown_delay", "must not be negative")
+	check(cfg.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+
+	check(slices.Contains(drivers, cfg.Database.Driver), "database.driver", "must be one of %v, got %q", drivers, cfg.Database.Driver)
+	if cfg.Database.Driver == DriverPostgres {
+		check(cfg.Database.Host != "", "database.host", "is required")
+		check(cfg.Database.Port != "", "database.port", "is required")
+		check(cfg.Database.User != "", "database.user", "is required")
+		check(cfg.Database.Name != "", "database.name", "is required")
+		check(slices.Contains(sslModes, cfg.Database.SSlMode), "database.ssl_mode", "must be one of %v, got %q", sslModes, cfg.Database.SSlMode)
+	}
+	if cfg.Database.Driver == DriverSQLite {
+		check(cfg.Database.Path != "", "database.path", "is required")
+	}
+	check(cfg.Database.StatementTimeout >= 0, "database.statement_timeout", "must not be negative")
+	check(cfg.Database.MaxConns >= 0, "database.max_conns", "must not be negative")
+	check(cfg.Database.MinConns >= 0, "database.min_conns", "must not be negative")
+	if cfg.Database.MaxConns > 0 {
+		check(cfg.Database.MinConns <= cfg.Database.MaxConns, "database.min_conns", "must not exceed database.max_conns")
+	}
+	check(cfg.Database.MaxConnLifetime >= 0, "database.max_conn_lifetime", "must not be negative")
+	check(cfg.Database.MaxConnIdleTime >= 0, "database.max_conn_idle_time", "must not be negative")
+	check(cfg.Database.HealthCheckPeriod >= 0, "database.health_check_period", "must not be negative")
+	check(cfg.Database.ConnectAttempts >= 1, "database.connect_attempts", "must be at least 1")
+	check(cfg.Database.ConnectBackoff >= 0, "database.connect_backoff", "must not be negative")
+	check(cfg.Database.ConnectMaxBackoff >= cfg.Database.ConnectBackoff, "database.connect_max_backoff", "must not be less than database.connect_backoff")
+	check(cfg.Database.ReplicaConnectTimeout >= 0, "database.replica_connect_timeout", "must not be negative")
+	check(cfg.Database.ReplicaCooldown >= 0, "database.replica_cooldown", "must not be negative")
+
+	check(slices.Contains(logLevels, cfg.Logger.Level), "logger.level", "must be one of %v, got %q", logLevels, cfg.Logger.Level)
+
+	check(cfg.Migration.Dir != "", "migrations.dir", "is required")
+
+	check(cfg.Auth.MaxFailedAttempts >= 0, "auth.max_failed_attempts", "must not be negative")
+	check(cfg.Auth.MaxFailedAttemptsPerIP >= 0, "auth.max_failed_attempts_per_ip", "must not be negative")
+	if cfg.Auth.MaxFailedAttempts > 0 || cfg.Auth.MaxFailedAttemptsPerIP > 0 {
+		check(cfg.Auth.FailureWindow > 0, "auth.failure_window", "must be positive when lockout is enabled")
+		check(cfg.Auth.BaseLockout > 0, "auth.base_lockout", "must be positive when lockout is enabled")
+		check(cfg.Auth.MaxLockout >= cfg.Auth.BaseLockout, "auth.max_lockout", "must not be less than auth.base_lockout")
+	}
+
+	check(cfg.Redis.DB >= 0, "redis.db", "must not be negative")
+	if cfg.Redis.Enabled() {
+		check(cfg.Redis.Port != "", "redis.port", "is required when redis.host is set")
+	}
+
+	for _, limit := range []struct {
+		name string
+		rule RateLimitRule
+	}{
+		{"credentials", cfg.RateLimit.Credentials},
+		{"auth", cfg.RateLimit.Auth},
+		{"voting", cfg.RateLimit.Voting},
+		{"writes", cfg.RateLimit.Writes},
+		{"reads", cfg.RateLimit.Reads},
+	} {
+		if limit.rule.Rate > 0 {
+			check(limit.rule.Burst >= 1, "rate_limit."+limit.name+".burst", "must be at least 1 when rate is set")
+		}
+	}
+
+	check(cfg.Idempotency.TTL > 0, "idempotency.ttl", "must be positive")
+	check(cfg.Idempotency.PurgeInterval >= 0, "idempotency.purge_interval", "must not be negative")
+	check(cfg.SoftDelete.Retention >= 0, "soft_delete.retention", "must not be negative")
+	check(cfg.SoftDelete.PurgeInterval >= 0, "soft_delete.purge_interval", "must not be negative")
+
+	if cfg.Tracing.Enabled {
+		check(slices.Contains(traceExporters, cfg.Tracing.Exporter), "tracing.exporter", "must be one of %v, got %q", traceExporters, cfg.Tracing.Exporter)
+		check(cfg.Tracing.ServiceName != "", "tracing.service_name", "is required")
+		check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be in 0..1")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package apperrors
+
+import "errors"
+
+// Ошибка приложения: машиночитаемый код, HTTP статус, текст (английский, для логов;
+// клиенту отдается перевод из i18n), ошибки по полям и исходная причина
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	Cause   error
+}
+
+// Ошибка валидации одного поля запроса
+type FieldError struct {
+	Field   string
+	Rule    string
+	Param   string
+	Message string
+}
+
+func New(code string, status int, message string) *AppError {
+	return &AppError{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Копии с причиной или полями равны исходной ошибке по коду,
+// поэтому errors.Is(err, ErrUserNotFound) работает и для них
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+func (e *AppError) WithCause(cause error) *AppError {
+	c := *e
+	c.Cause = cause
+	return &c
+}
+
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	c := *e
+	c.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &c
+}
+
+// Любую ошибку приводит к AppError, неизвестные становятся внутренними
+func From(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return ErrInternal.WithCause(err)
+}
@@ -1,23 +1,57 @@
 package apperrors
 
-import "errors"
+import "net/http"
 
 var (
+	// Common errors
+	ErrInternal      = New("internal", http.StatusInternalServerError, "internal server error")
+	ErrInvalidBody   = New("invalid_body", http.StatusBadRequest, "request body is not valid JSON")
+	ErrValidation    = New("validation_failed", http.StatusBadRequest, "request validation failed")
+	ErrInvalidLimit  = New("invalid_limit", http.StatusBadRequest, "limit must be a number")
+	ErrInvalidOffset = New("invalid_offset", http.StatusBadRequest, "offset must be a number")
+
 	// User errors
-	ErrUserAlreadyExist     = errors.New("user already exist")
-	ErrUserNotFound         = errors.New("user not found")
-	ErrNothingToChange      = errors.New("nothing to change")
-	ErrFailedToHashPassword = errors.New("failed to hash password")
+	ErrUserAlreadyExist     = New("user_already_exist", http.StatusConflict, "user already exist")
+	ErrUserNotFound         = New("user_not_found", http.StatusNotFound, "user not found")
+	ErrNothingToChange      = New("nothing_to_change", http.StatusBadRequest, "nothing to change")
+	ErrFailedToHashPassword = New("failed_to_hash_password", http.StatusInternalServerError, "failed to hash password")
+	ErrWrongPassword        = New("wrong_password", http.StatusForbidden, "current password is incorrect")
+
+	// Concurrency errors
+	ErrVersionConflict      = New("version_conflict", http.StatusPreconditionFailed, "resource was modified, refetch it and retry")
+	ErrPreconditionRequired = New("precondition_required", http.StatusPreconditionRequired, "If-Match header is required")
+	ErrInvalidIfMatch       = New("invalid_if_match", http.StatusBadRequest, "If-Match header must be a list of ETags or *")
+
+	// Auth errors
+	ErrInvalidCredentials   = New("invalid_credentials", http.StatusUnauthorized, "invalid credentials")
+	ErrUnauthorized         = New("unauthorized", http.StatusUnauthorized, "authentication required")
+	ErrForbidden            = New("forbidden", http.StatusForbidden, "not enough permissions")
+	ErrCannotChangeOwnRole  = New("cannot_change_own_role", http.StatusConflict, "cannot change own role")
+	ErrAccountLocked        = New("account_locked", http.StatusLocked, "account is temporarily locked")
+	ErrTooManyLoginAttempts = New("too_many_login_attempts", http.StatusTooManyRequests, "too many login attempts")
+	ErrRateLimited          = New("rate_limited", http.StatusTooManyRequests, "rate limit exceeded")
 
 	// Election errors
-	ErrElectionAlreadyExist = errors.New("election already exist")
-	ErrElectionNotFound     = errors.New("election not found")
+	ErrElectionAlreadyExist = New("election_already_exist", http.StatusConflict, "election already exist")
+	ErrElectionNotFound     = New("election_not_found", http.StatusNotFound, "election not found")
+	ErrElectionClosed       = New("election_closed", http.StatusConflict, "election is closed")
 
 	// Vote Variant errors
-	ErrVoteVariantAlreadyExist = errors.New("vote variant already exist")
-	ErrVoteVariantNotFound     = errors.New("vote variant not found")
+	ErrVoteVariantAlreadyExist = New("vote_variant_already_exist", http.StatusConflict, "vote variant already exist")
+	ErrVoteVariantNotFound     = New("vote_variant_not_found", http.StatusNotFound, "vote variant not found")
+
+	// Translation errors
+	ErrTranslationNotFound = New("translation_not_found", http.StatusNotFound, "translation not found")
+
+	// Idempotency errors
+	ErrIdempotencyKeyNotFound   = New("idempotency_key_not_found", http.StatusInternalServerError, "idempotency key not found")
+	ErrIdempotencyKeyReused     = New("idempotency_key_reused", http.StatusUnprocessableEntity, "idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = New("idempotency_key_in_progress", http.StatusConflict, "request with this idempotency key is still in progress")
+	ErrInvalidIdempotencyKey    = New("invalid_idempotency_key", http.StatusBadRequest, "idempotency key must be 1-255 characters")
 
 	// vote errors
-	ErrVoteAlreadyExist = errors.New("vote Already Exist")
-	ErrVoteNotFound     = errors.New("vote not found")
+	ErrVoteAlreadyExist  = New("vote_already_exist", http.StatusConflict, "vote Already Exist")
+	ErrVoteNotFound      = New("vote_not_found", http.StatusNotFound, "vote not found")
+	ErrVoteIsFinal       = New("vote_is_final", http.StatusConflict, "votes in this election are final and cannot be changed")
+	ErrVoteWrongElection = New("vote_wrong_election", http.StatusBadRequest, "vote variant belongs to another election")
 )
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	checkTimeout = 2 * time.Second
+)
+
+// Проверка зависимости, nil - зависимость доступна
+type Check func(ctx context.Context) error
+
+type Result struct {
+	Status  string
+	Latency time.Duration
+	Err     error
+}
+
+type Checker struct {
+	mu           sync.RWMutex
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+}
+
+func New() *Checker {
+	return &Checker{
+		checks: make(map[string]Check),
+	}
+}
+
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// После сигнала остановки readiness отвечает not ready, чтобы балансировщик снял трафик
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Проверки выполняются параллельно, каждая ограничена checkTimeout
+func (c *Checker) Check(ctx context.Context) (bool, map[string]Result) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		resMu   sync.Mutex
+		results = make(map[string]Result, len(c.checks))
+	)
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, check)
+			resMu.Lock()
+			results[name] = result
+			resMu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	ready := !c.ShuttingDown()
+	for _, result := range results {
+		if result.Status != StatusUp {
+			ready = false
+		}
+	}
+
+	return ready, results
+}
+
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := Result{
+		Status:  StatusUp,
+		Latency: time.Since(start),
+		Err:     err,
+	}
+	if err != nil {
+		result.Status = StatusDown
+	}
+
+	return result
+}
@@ -0,0 +1,137 @@
+package i18n
+
+// Ключи: error.<код AppError>, validation.<правило валидатора>
+var catalogs = map[string]map[string]string{
+	LocaleEN: {
+		// Common errors
+		"error.internal":          "internal server error",
+		"error.invalid_body":      "request body is not valid JSON",
+		"error.validation_failed": "request validation failed",
+		"error.invalid_limit":     "limit must be a number",
+		"error.invalid_offset":    "offset must be a number",
+
+		// User errors
+		"error.user_already_exist":      "user already exist",
+		"error.user_not_found":          "user not found",
+		"error.nothing_to_change":       "nothing to change",
+		"error.failed_to_hash_password": "failed to hash password",
+		"error.wrong_password":          "current password is incorrect",
+
+		// Concurrency errors
+		"error.version_conflict":      "resource was modified, refetch it and retry",
+		"error.precondition_required": "If-Match header is required",
+		"error.invalid_if_match":      "If-Match header must be a single ETag or *",
+
+		// Auth errors
+		"error.invalid_credentials":     "invalid credentials",
+		"error.unauthorized":            "authentication required",
+		"error.forbidden":               "not enough permissions",
+		"error.cannot_change_own_role":  "cannot change own role",
+		"error.account_locked":          "account is temporarily locked",
+		"error.too_many_login_attempts": "too many login attempts",
+		"error.rate_limited":            "rate limit exceeded",
+
+		// Election errors
+		"error.election_already_exist": "election already exist",
+		"error.election_not_found":     "election not found",
+		"error.election_closed":        "election is closed",
+
+		// Vote Variant errors
+		"error.vote_variant_already_exist": "vote variant already exist",
+		"error.vote_variant_not_found":     "vote variant not found",
+
+		// Translation errors
+		"error.translation_not_found": "translation not found",
+
+		// Idempotency errors
+		"error.idempotency_key_not_found":   "idempotency key not found",
+		"error.idempotency_key_reused":      "idempotency key was already used with a different request",
+		"error.idempotency_key_in_progress": "request with this idempotency key is still in progress",
+		"error.invalid_idempotency_key":     "idempotency key must be 1-255 characters",
+
+		// vote errors
+		"error.vote_already_exist":  "vote already exist",
+		"error.vote_not_found":      "vote not found",
+		"error.vote_is_final":       "votes in this election are final and cannot be changed",
+		"error.vote_wrong_election": "vote variant belongs to another election",
+
+		// Validation
+		"validation.required":  "{field} is required",
+		"validation.uuid":      "{field} must be a valid UUID",
+		"validation.alphanum":  "{field} must contain only letters and digits",
+		"validation.min":       "{field} must be at least {param} characters long",
+		"validation.max":       "{field} must be at most {param} characters long",
+		"validation.oneof":     "{field} must be one of: {param}",
+		"validation.datetime":  "{field} must be a date-time in RFC 3339 format",
+		"validation.nefield":   "{field} must differ from {param}",
+		"validation.notbefore": "{field} must not be earlier than {param}",
+		"validation.notblank":  "{field} must not be blank",
+		"validation.default":   "{field} is invalid",
+	},
+	LocaleRU: {
+		// Common errors
+		"error.internal":          "внутренняя ошибка сервера",
+		"error.invalid_body":      "тело запроса не является корректным JSON",
+		"error.validation_failed": "запрос не прошел проверку",
+		"error.invalid_limit":     "limit должен быть числом",
+		"error.invalid_offset":    "offset должен быть числом",
+
+		// User errors
+		"error.user_already_exist":      "пользователь уже существует",
+		"error.user_not_found":          "пользователь не найден",
+		"error.nothing_to_change":       "нечего изменять",
+		"error.failed_to_hash_password": "не удалось захэшировать пароль",
+		"error.wrong_password":          "текущий пароль указан неверно",
+
+		// Concurrency errors
+		"error.version_conflict":      "ресурс был изменен, получите его заново и повторите запрос",
+		"error.precondition_required": "требуется заголовок If-Match",
+		"error.invalid_if_match":      "заголовок If-Match должен содержать один ETag или *",
+
+		// Auth errors
+		"error.invalid_credentials":     "неверный логин или пароль",
+		"error.unauthorized":            "требуется аутентификация",
+		"error.forbidden":               "недостаточно прав",
+		"error.cannot_change_own_role":  "нельзя изменить собственную роль",
+		"error.account_locked":          "учетная запись временно заблокирована",
+		"error.too_many_login_attempts": "слишком много попыток входа",
+		"error.rate_limited":            "превышен лимит запросов",
+
+		// Election errors
+		"error.election_already_exist": "выборы уже существуют",
+		"error.election_not_found":     "выборы не найдены",
+		"error.election_closed":        "выборы закрыты",
+
+		// Vote Variant errors
+		"error.vote_variant_already_exist": "вариант голосования уже существует",
+		"error.vote_variant_not_found":     "вариант голосования не найден",
+
+		// Translation errors
+		"error.translation_not_found": "перевод не найден",
+
+		// Idempotency errors
+		"error.idempotency_key_not_found":   "ключ идемпотентности не найден",
+		"error.idempotency_key_reused":      "ключ идемпотентности уже использован с другим запросом",
+		"error.idempotency_key_in_progress": "запрос с этим ключом идемпотентности еще выполняется",
+		"error.invalid_idempotency_key":     "ключ идемпотентности должен содержать от 1 до 255 символов",
+
+		// vote errors
+		"error.vote_already_exist":  "голос уже существует",
+		"error.vote_not_found":      "голос не найден",
+		"error.vote_is_final":       "голоса в этих выборах окончательны и не могут быть изменены",
+		"error.vote_wrong_election": "вариант относится к другим выборам",
+
+		// Validation
+		"validation.required":  "поле {field} обязательно",
+		"validation.uuid":      "поле {field} должно быть корректным UUID",
+		"validation.alphanum":  "поле {field} может содержать только буквы и цифры",
+		"validation.min":       "поле {field} должно содержать не менее {param} символов",
+		"validation.max":       "поле {field} должно содержать не более {param} символов",
+		"validation.oneof":     "поле {field} должно быть одним из: {param}",
+		"validation.datetime":  "поле {field} должно быть датой и временем в формате RFC 3339",
+		"validation.nefield":   "поле {field} должно отличаться от {param}",
+		"validation.notbefore": "поле {field} не может быть раньше {param}",
+		"validation.notblank":  "поле {field} не может состоять только из пробелов",
+		"validation.default":   "поле {field} заполнено некорректно",
+	},
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	LocaleEN = "en"
+	LocaleRU = "ru"
+
+	DefaultLocale = LocaleEN
+)
+
+// Поддерживаемые языки: сообщения API и переводы выборов и вариантов
+var Locales = []string{LocaleEN, LocaleRU}
+
+func Supported(locale string) bool {
+	return slices.Contains(Locales, locale)
+}
+
+// Выбирает поддерживаемый язык с наибольшим q из Accept-Language (ru-RU считается ru)
+func ParseAcceptLanguage(header string) string {
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if Supported(lang) && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+
+	return best
+}
+
+type contextKey struct{}
+
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// Язык запроса или язык по умолчанию, если middleware не отработал
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Сообщение по ключу на языке locale, при отсутствии - на языке по умолчанию
+func Message(locale, key string) (string, bool) {
+	if message, ok := catalogs[locale][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[DefaultLocale][key]
+	return message, ok
+}
+
+// Сообщение с подстановкой {name} из args, fallback - если ключа нет ни в одном каталоге
+func Format(locale, key, fallback string, args map[string]string) string {
+	message, ok := Message(locale, key)
+	if !ok {
+		message = fallback
+	}
+
+	replacements := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(message)
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
 	"github.com/alonsoF100/golos/internal/config"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Setup(cfg *config.Config) *slog.Logger {
@@ -17,12 +19,28 @@ func Setup(cfg *config.Config) *slog.Logger {
 		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: parseLevel(cfg.Logger.Level)})
 	}
 
-	logger := slog.New(handler)
+	logger := slog.New(traceHandler{handler})
 	slog.SetDefault(logger)
 
 	return logger
 }
 
+type contextKey struct{}
+
+// Логгер запроса с request_id и прочими атрибутами, кладется middleware
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// Вне запроса (фоновые задачи) возвращает логгер по умолчанию
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
 func parseLevel(level string) slog.Level {
 	switch level {
 	case "debug":
@@ -37,3 +55,27 @@ func parseLevel(level string) slog.Level {
 		return slog.LevelDebug
 	}
 }
+
+// Добавляет trace_id и span_id в записи, сделанные с контекстом запроса (slog.*Context)
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "golos"
+
+// Отдельный registry, чтобы не тянуть глобальное состояние client_golang
+var Registry = prometheus.NewRegistry()
+
+// Доменные счетчики, инкрементируются в service
+var (
+	VotesCast = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_cast_total",
+		Help:      "Number of votes cast.",
+	})
+
+	ElectionsOpened = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "elections_opened_total",
+		Help:      "Number of elections created or reopened.",
+	})
+
+	ElectionsClosed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "elections_closed_total",
+		Help:      "Number of elections closed.",
+	})
+
+	AuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Number of failed authentication attempts by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		VotesCast,
+		ElectionsOpened,
+		ElectionsClosed,
+		AuthFailures,
+	)
+}
@@ -6,15 +6,70 @@ type User struct {
 	ID        string
 	Nickname  string
 	Password  string
+	Role      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64
 }
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type Election struct {
 	ID          string
 	UserID      string
 	Name        string
 	Description string
+	Status      string
+	VotePolicy  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Version     int64
+}
+
+const (
+	ElectionStatusOpen   = "open"
+	ElectionStatusClosed = "closed"
+)
+
+// Можно ли менять и отзывать голос: final - голос окончательный сразу,
+// changes_until_close - голос можно изменить, пока выборы открыты
+const (
+	VotePolicyFinal             = "final"
+	VotePolicyChangesUntilClose = "changes_until_close"
+)
+
+// Сортировка выборов
+const (
+	ElectionSortCreatedAt = "created_at"
+	ElectionSortUpdatedAt = "updated_at"
+	ElectionSortName      = "name"
+	ElectionSortRelevance = "relevance"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Фильтр для выборки выборов, пустые поля не учитываются
+type ElectionFilter struct {
+	UserID        string
+	Query         string
+	Status        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	SortBy        string
+	SortOrder     string
+}
+
+// Перевод названия и описания выборов на язык Locale
+type ElectionTranslation struct {
+	ElectionID  string
+	Locale      string
+	Name        string
+	Description string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -25,6 +80,15 @@ type VoteVariant struct {
 	Name       string
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	Version    int64
+}
+
+type VoteVariantTranslation struct {
+	VoteVariantID string
+	Locale        string
+	Name          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 type Vote struct {
@@ -34,3 +98,112 @@ type Vote struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Изменение голоса, OldVariantID = "" - первоначальный голос
+// Итог по варианту: число действующих голосов
+type VariantResult struct {
+	VariantID string
+	Name      string
+	Votes     int64
+}
+
+// Пустой OldVariantID - первоначальный голос, пустой NewVariantID - отзыв голоса
+type VoteChange struct {
+	ID           string
+	VoteID       string
+	UserID       string
+	OldVariantID string
+	NewVariantID string
+	ChangedAt    time.Time
+}
+
+// Счетчик неудачных входов по ключу (nickname:... или ip:...)
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+type LoginLockout struct {
+	ID          string
+	Key         string
+	Failures    int
+	LockedUntil time.Time
+	CreatedAt   time.Time
+}
+
+// Сохраненный ответ на POST с Idempotency-Key, StatusCode = 0 - запрос еще выполняется
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ETag        string
+	Location    string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Сколько мягко удаленных записей удалено окончательно
+type PurgeResult struct {
+	Users        int64
+	Elections    int64
+	VoteVariants int64
+}
+
+// Кто и в рамках какого запроса выполняет изменение, Actor = nil - аноним или система
+type RequestMeta struct {
+	Actor     *User
+	RequestID string
+}
+
+func (m RequestMeta) ActorID() string {
+	if m.Actor == nil {
+		return ""
+	}
+	return m.Actor.ID
+}
+
+// Запись журнала изменений, Before/After - JSON снимки сущности (nil, если ее не было)
+type AuditEvent struct {
+	ID         string
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     []byte
+	After      []byte
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionPatch          = "patch"
+	AuditActionDelete         = "delete"
+	AuditActionRestore        = "restore"
+	AuditActionPurge          = "purge"
+	AuditActionChangePassword = "change_password"
+	AuditActionResetPassword  = "reset_password"
+	AuditActionSetRole        = "set_role"
+	AuditActionClose          = "close"
+	AuditActionTranslate      = "translate"
+	AuditActionUntranslate    = "untranslate"
+
+	AuditEntityUser        = "user"
+	AuditEntityElection    = "election"
+	AuditEntityVoteVariant = "vote_variant"
+	AuditEntityVote        = "vote"
+	AuditEntityPurge       = "purge"
+)
+
+// Фильтр журнала изменений, пустые поля не учитываются
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	From       *time.Time
+	To         *time.Time
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const loginAttemptPrefix = "golos:login:"
+
+func failuresKey(key string) string {
+	return loginAttemptPrefix + key + ":failures"
+}
+
+func lockKey(key string) string {
+	return loginAttemptPrefix + key + ":lock"
+}
+
+func (r Repository) RegisterLoginFailure(ctx context.Context, key string, failedAt time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	pp := "internal/repository/cache/redis/RegisterLoginFailure"
+
+	var incr *goredis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		incr = pipe.Incr(ctx, failuresKey(key))
+		// Окно продлевается с каждой ошибкой, но не сокращает TTL, выставленный блокировкой
+		pipe.ExpireGT(ctx, failuresKey(key), window)
+		pipe.ExpireNX(ctx, failuresKey(key), window)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	attempt, err := r.GetLoginAttempt(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	attempt.Failures = int(incr.Val())
+	attempt.LastFailureAt = failedAt
+
+	return attempt, nil
+}
+
+func (r Repository) SetLoginLock(ctx context.Context, key string, lockedUntil time.Time) error {
+	pp := "internal/repository/cache/redis/SetLoginLock"
+
+	ttl := time.Until(lockedUntil)
+	if ttl <= 0 {
+		return nil
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, lockKey(key), lockedUntil.Unix(), ttl)
+		// Счетчик должен пережить блокировку, чтобы следующая вырастала экспоненциально
+		pipe.ExpireGT(ctx, failuresKey(key), 2*ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	pp := "internal/repository/cache/redis/GetLoginAttempt"
+
+	attempt := &models.LoginAttempt{Key: key}
+
+	failures, err := r.client.Get(ctx, failuresKey(key)).Int()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	attempt.Failures = failures
+
+	lockedUntil, err := r.client.Get(ctx, lockKey(key)).Result()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return attempt, nil
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	unix, err := strconv.ParseInt(lockedUntil, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	until := time.Unix(unix, 0)
+	attempt.LockedUntil = &until
+
+	return attempt, nil
+}
+
+func (r Repository) ResetLoginAttempts(ctx context.Context, key string) error {
+	pp := "internal/repository/cache/redis/ResetLoginAttempts"
+
+	err := r.client.Del(ctx, failuresKey(key), lockKey(key)).Err()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/alonsoF100/golos/internal/config"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type Repository struct {
+	client *goredis.Client
+}
+
+func New(client *goredis.Client) *Repository {
+	return &Repository{
+		client: client,
+	}
+}
+
+func NewClient(cfg *config.Config) (*goredis.Client, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password.Value(),
+		DB:       cfg.Redis.DB,
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r Repository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
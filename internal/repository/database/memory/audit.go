@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// Журнал только дополняется
+func (r *Repository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *event
+	stored.Before = bytes.Clone(event.Before)
+	stored.After = bytes.Clone(event.After)
+	r.auditEvents = append(r.auditEvents, stored)
+
+	return nil
+}
+
+func (r *Repository) GetAuditEvents(ctx context.Context, limit, offset int, filter models.AuditFilter) ([]*models.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.AuditEvent
+	for _, event := range r.auditEvents {
+		if filter.EntityType != "" && event.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && event.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.ActorID != "" && event.ActorID != filter.ActorID {
+			continue
+		}
+		if filter.From != nil && event.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && event.CreatedAt.After(*filter.To) {
+			continue
+		}
+		events = append(events, event)
+	}
+	slices.SortFunc(events, func(a, b models.AuditEvent) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	var result []*models.AuditEvent
+	for _, event := range page(events, limit, offset) {
+		event.Before = bytes.Clone(event.Before)
+		event.After = bytes.Clone(event.After)
+		result = append(result, &event)
+	}
+
+	return result, nil
+}
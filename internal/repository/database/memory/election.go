@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r *Repository) CreateElection(ctx context.Context, id, userID, name string, description string, votePolicy string, createdAt time.Time, updatedAt time.Time) (*models.Election, error) {
+	pp := "internal/database/memory/repository/CreateElection"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.elections[id]; ok {
+		return nil, fmt.Errorf("%s: error: %w", pp, errDuplicateID)
+	}
+	if user, ok := r.users[userID]; !ok || user.deletedAt != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	row := &electionRow{
+		Election: models.Election{
+			ID:          id,
+			UserID:      userID,
+			Name:        name,
+			Description: description,
+			Status:      models.ElectionStatusOpen,
+			VotePolicy:  votePolicy,
+			CreatedAt:   createdAt,
+			UpdatedAt:   updatedAt,
+			Version:     1,
+		},
+		seq: r.nextSeq(),
+	}
+	r.elections[id] = row
+
+	election := row.Election
+	return &election, nil
+}
+
+func (r *Repository) GetElections(ctx context.Context, limit, offset int, filter models.ElectionFilter) ([]*models.Election, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := searchTerms(filter.Query)
+
+	var rows []*electionRow
+	for _, row := range r.elections {
+		if row.deletedAt != nil {
+			continue
+		}
+		if filter.UserID != "" && row.UserID != filter.UserID {
+			continue
+		}
+		if len(query) > 0 && rank(row.Election, query) == 0 {
+			continue
+		}
+		if filter.Status != "" && row.Status != filter.Status {
+			continue
+		}
+		if filter.CreatedAfter != nil && row.CreatedAt.Before(*filter.CreatedAfter) {
+			continue
+		}
+		if filter.CreatedBefore != nil && row.CreatedAt.After(*filter.CreatedBefore) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, electionOrder(filter, query))
+
+	var elections []*models.Election
+	for _, row := range page(rows, limit, offset) {
+		election := row.Election
+		elections = append(elections, &election)
+	}
+
+	return elections, nil
+}
+
+func (r *Repository) GetElection(ctx context.Context, id string) (*models.Election, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.elections[id]
+	if !ok || row.deletedAt != nil {
+		return nil, apperrors.ErrElectionNotFound
+	}
+
+	election := row.Election
+	return &election, nil
+}
+
+func (r *Repository) DeleteElection(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.elections[id]
+	if !ok {
+		return apperrors.ErrElectionNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrElectionNotFound); err != nil {
+		return err
+	}
+
+	row.deletedAt = &deletedAt
+	row.Version++
+
+	return nil
+}
+
+func (r *Repository) PatchElection(ctx context.Context, id string, userID, name, description, status, votePolicy *string, version *int64, updatedAt time.Time) (*models.Election, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Как и в SQL-бэкендах, владелец проверяется раньше версии выборов
+	if userID != nil {
+		if user, ok := r.users[*userID]; !ok || user.deletedAt != nil {
+			return nil, apperrors.ErrUserNotFound
+		}
+	}
+	row, ok := r.elections[id]
+	if !ok {
+		return nil, apperrors.ErrElectionNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrElectionNotFound); err != nil {
+		return nil, err
+	}
+
+	if userID != nil {
+		row.UserID = *userID
+	}
+	if name != nil {
+		row.Name = *name
+	}
+	if description != nil {
+		row.Description = *description
+	}
+	if status != nil {
+		row.Status = *status
+	}
+	if votePolicy != nil {
+		row.VotePolicy = *votePolicy
+	}
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	election := row.Election
+	return &election, nil
+}
+
+func (r *Repository) RestoreElection(ctx context.Context, id string, updatedAt time.Time) (*models.Election, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.elections[id]
+	if !ok || row.deletedAt == nil {
+		return nil, apperrors.ErrElectionNotFound
+	}
+
+	row.deletedAt = nil
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	election := row.Election
+	return &election, nil
+}
+
+func (r *Repository) PurgeElections(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, row := range r.elections {
+		if purgeable(row.deletedAt, deletedBefore) {
+			r.deleteElectionCascade(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// Та же сортировка, что orderElections в postgres
+func electionOrder(filter models.ElectionFilter, query []string) func(a, b *electionRow) int {
+	direction := -1
+	if filter.SortOrder == models.SortOrderAsc {
+		direction = 1
+	}
+	byCreatedAt := func(a, b *electionRow) int {
+		return direction * newestFirst(b.CreatedAt, a.CreatedAt, b.seq, a.seq)
+	}
+	newest := func(a, b *electionRow) int {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.seq, b.seq)
+	}
+
+	switch filter.SortBy {
+	case models.ElectionSortRelevance:
+		if len(query) == 0 {
+			return byCreatedAt
+		}
+		return func(a, b *electionRow) int {
+			if c := direction * compareInt(rank(a.Election, query), rank(b.Election, query)); c != 0 {
+				return c
+			}
+			return newest(a, b)
+		}
+	case models.ElectionSortName:
+		return func(a, b *electionRow) int {
+			if c := direction * strings.Compare(a.Name, b.Name); c != 0 {
+				return c
+			}
+			return newest(a, b)
+		}
+	case models.ElectionSortUpdatedAt:
+		return func(a, b *electionRow) int {
+			return direction * newestFirst(b.UpdatedAt, a.UpdatedAt, b.seq, a.seq)
+		}
+	default:
+		return byCreatedAt
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Приближение plainto_tsquery('simple', ...): слова в нижнем регистре,
+// найдены должны быть все, ранг - число вхождений
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func rank(election models.Election, query []string) int {
+	words := searchTerms(election.Name + " " + election.Description)
+
+	total := 0
+	for _, term := range query {
+		count := 0
+		for _, word := range words {
+			if word == term {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		total += count
+	}
+
+	return total
+}
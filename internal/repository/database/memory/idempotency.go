@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// Занимает ключ, если его нет или он уже истек, false - ключ занят другим запросом
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, key, requestHash string, createdAt time.Time, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record, ok := r.idempotencyKeys[key]; ok && !record.ExpiresAt.Before(createdAt) {
+		return false, nil
+	}
+
+	r.idempotencyKeys[key] = models.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   createdAt,
+		ExpiresAt:   expiresAt,
+	}
+
+	return true, nil
+}
+
+func (r *Repository) GetIdempotencyRecord(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.idempotencyKeys[key]
+	if !ok {
+		return nil, apperrors.ErrIdempotencyKeyNotFound
+	}
+	record.Body = bytes.Clone(record.Body)
+
+	return &record, nil
+}
+
+func (r *Repository) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.idempotencyKeys[key]
+	if !ok {
+		return apperrors.ErrIdempotencyKeyNotFound
+	}
+	record.StatusCode = statusCode
+	record.ETag = etag
+	record.Location = location
+	record.Body = bytes.Clone(body)
+	r.idempotencyKeys[key] = record
+
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.idempotencyKeys, key)
+
+	return nil
+}
+
+func (r *Repository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.idempotencyKeys {
+		if record.ExpiresAt.Before(now) {
+			delete(r.idempotencyKeys, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
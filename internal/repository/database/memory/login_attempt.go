@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// Счетчик сбрасывается, только если и последняя ошибка, и блокировка старше окна
+func (r *Repository) RegisterLoginFailure(ctx context.Context, key string, failedAt time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	windowStart := failedAt.Add(-window)
+
+	attempt, ok := r.loginAttempts[key]
+	switch {
+	case !ok:
+		attempt = models.LoginAttempt{Key: key, Failures: 1}
+	case attempt.LastFailureAt.Before(windowStart) && (attempt.LockedUntil == nil || attempt.LockedUntil.Before(windowStart)):
+		attempt.Failures = 1
+	default:
+		attempt.Failures++
+	}
+	attempt.LastFailureAt = failedAt
+	r.loginAttempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (r *Repository) SetLoginLock(ctx context.Context, key string, lockedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.loginAttempts[key]; ok {
+		attempt.LockedUntil = &lockedUntil
+		r.loginAttempts[key] = attempt
+	}
+
+	return nil
+}
+
+func (r *Repository) GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attempt, ok := r.loginAttempts[key]
+	if !ok {
+		return &models.LoginAttempt{Key: key}, nil
+	}
+
+	return &attempt, nil
+}
+
+func (r *Repository) ResetLoginAttempts(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.loginAttempts, key)
+
+	return nil
+}
+
+func (r *Repository) CreateLoginLockout(ctx context.Context, id, key string, failures int, lockedUntil time.Time, createdAt time.Time) (*models.LoginLockout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lockout := models.LoginLockout{
+		ID:          id,
+		Key:         key,
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+		CreatedAt:   createdAt,
+	}
+	r.loginLockouts = append(r.loginLockouts, lockout)
+
+	return &lockout, nil
+}
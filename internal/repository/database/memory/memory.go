@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+)
+
+var _ service.Storage = (*Repository)(nil)
+
+// Повтор первичного ключа, в postgres это необработанная ошибка вставки
+var errDuplicateID = errors.New("duplicate id")
+
+// Хранилище в памяти для демо и быстрых тестов: те же проверки уникальности,
+// внешних ключей и версий, что и в postgres. Данные теряются при перезапуске
+type Repository struct {
+	mu  sync.RWMutex
+	seq uint64
+
+	users                   map[string]*userRow
+	elections               map[string]*electionRow
+	voteVariants            map[string]*voteVariantRow
+	votes                   map[string]*voteRow
+	voteHistory             []voteChangeRow
+	electionTranslations    map[translationKey]models.ElectionTranslation
+	voteVariantTranslations map[translationKey]models.VoteVariantTranslation
+	roles                   map[string]string
+	loginAttempts           map[string]models.LoginAttempt
+	loginLockouts           []models.LoginLockout
+	idempotencyKeys         map[string]models.IdempotencyRecord
+	auditEvents             []models.AuditEvent
+}
+
+func New() *Repository {
+	return &Repository{
+		users:                   make(map[string]*userRow),
+		elections:               make(map[string]*electionRow),
+		voteVariants:            make(map[string]*voteVariantRow),
+		votes:                   make(map[string]*voteRow),
+		electionTranslations:    make(map[translationKey]models.ElectionTranslation),
+		voteVariantTranslations: make(map[translationKey]models.VoteVariantTranslation),
+		roles:                   make(map[string]string),
+		loginAttempts:           make(map[string]models.LoginAttempt),
+		idempotencyKeys:         make(map[string]models.IdempotencyRecord),
+	}
+}
+
+// seq сохраняет порядок вставки там, где postgres сортирует по совпадающим created_at
+type userRow struct {
+	models.User
+	deletedAt *time.Time
+	seq       uint64
+}
+
+type electionRow struct {
+	models.Election
+	deletedAt *time.Time
+	seq       uint64
+}
+
+type voteVariantRow struct {
+	models.VoteVariant
+	deletedAt *time.Time
+	seq       uint64
+}
+
+type voteRow struct {
+	models.Vote
+	seq uint64
+}
+
+type voteChangeRow struct {
+	models.VoteChange
+	seq uint64
+}
+
+type translationKey struct {
+	id     string
+	locale string
+}
+
+// Вызывается под r.mu
+func (r *Repository) nextSeq() uint64 {
+	r.seq++
+	return r.seq
+}
+
+// Аналог withVersion и missingOrConflict: удаленная строка не найдена,
+// устаревшая версия - конфликт
+func checkVersion(deletedAt *time.Time, current int64, version *int64, notFound error) error {
+	if deletedAt != nil {
+		return notFound
+	}
+	if version != nil && *version != current {
+		return apperrors.ErrVersionConflict
+	}
+	return nil
+}
+
+// Удалена окончательно ли строка при очистке с порогом before
+func purgeable(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
+
+// LIMIT/OFFSET, отрицательный limit в postgres превращается в огромный uint64
+func page[T any](items []T, limit, offset int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// Сначала новые, при равном времени - позже вставленные
+func newestFirst(aTime, bTime time.Time, aSeq, bSeq uint64) int {
+	if c := bTime.Compare(aTime); c != 0 {
+		return c
+	}
+	return compareSeq(bSeq, aSeq)
+}
+
+func compareSeq(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Каскадное удаление как ON DELETE CASCADE во внешних ключах postgres.
+// История голосов не удаляется: у vote_history нет внешнего ключа на votes.
+// Вызываются под r.mu
+func (r *Repository) deleteUserCascade(id string) {
+	delete(r.users, id)
+	delete(r.roles, id)
+	for electionID, election := range r.elections {
+		if election.UserID == id {
+			r.deleteElectionCascade(electionID)
+		}
+	}
+	for voteID, vote := range r.votes {
+		if vote.UserID == id {
+			delete(r.votes, voteID)
+		}
+	}
+}
+
+func (r *Repository) deleteElectionCascade(id string) {
+	delete(r.elections, id)
+	for key := range r.electionTranslations {
+		if key.id == id {
+			delete(r.electionTranslations, key)
+		}
+	}
+	for voteVariantID, voteVariant := range r.voteVariants {
+		if voteVariant.ElectionID == id {
+			r.deleteVoteVariantCascade(voteVariantID)
+		}
+	}
+}
+
+func (r *Repository) deleteVoteVariantCascade(id string) {
+	delete(r.voteVariants, id)
+	for key := range r.voteVariantTranslations {
+		if key.id == id {
+			delete(r.voteVariantTranslations, key)
+		}
+	}
+	for voteID, vote := range r.votes {
+		if vote.VariantID == id {
+			delete(r.votes, voteID)
+		}
+	}
+}
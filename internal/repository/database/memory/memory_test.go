@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/alonsoF100/golos/internal/repository/database/storagetest"
+	"github.com/alonsoF100/golos/internal/service"
+)
+
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) service.Storage {
+		return New()
+	})
+}
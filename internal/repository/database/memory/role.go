@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// Отсутствие записи означает обычного пользователя
+func (r *Repository) GetUserRole(ctx context.Context, userID string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	role, ok := r.roles[userID]
+	if !ok {
+		return models.RoleUser, nil
+	}
+
+	return role, nil
+}
+
+func (r *Repository) SetUserRole(ctx context.Context, userID, role string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[userID]; !ok {
+		return apperrors.ErrUserNotFound
+	}
+	r.roles[userID] = role
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r *Repository) SetElectionTranslation(ctx context.Context, electionID, locale, name, description string, updatedAt time.Time) (*models.ElectionTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.elections[electionID]; !ok {
+		return nil, apperrors.ErrElectionNotFound
+	}
+
+	key := translationKey{id: electionID, locale: locale}
+	translation, ok := r.electionTranslations[key]
+	if !ok {
+		translation = models.ElectionTranslation{ElectionID: electionID, Locale: locale, CreatedAt: updatedAt}
+	}
+	translation.Name = name
+	translation.Description = description
+	translation.UpdatedAt = updatedAt
+	r.electionTranslations[key] = translation
+
+	return &translation, nil
+}
+
+func (r *Repository) DeleteElectionTranslation(ctx context.Context, electionID, locale string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := translationKey{id: electionID, locale: locale}
+	if _, ok := r.electionTranslations[key]; !ok {
+		return apperrors.ErrTranslationNotFound
+	}
+	delete(r.electionTranslations, key)
+
+	return nil
+}
+
+// Все переводы выборов electionIDs, locale = "" - на все языки
+func (r *Repository) GetElectionTranslations(ctx context.Context, electionIDs []string, locale string) ([]*models.ElectionTranslation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var translations []*models.ElectionTranslation
+	for key, translation := range r.electionTranslations {
+		if slices.Contains(electionIDs, key.id) && (locale == "" || key.locale == locale) {
+			translations = append(translations, &translation)
+		}
+	}
+	slices.SortFunc(translations, func(a, b *models.ElectionTranslation) int {
+		return compareKeys(a.ElectionID, a.Locale, b.ElectionID, b.Locale)
+	})
+
+	return translations, nil
+}
+
+func (r *Repository) SetVoteVariantTranslation(ctx context.Context, voteVariantID, locale, name string, updatedAt time.Time) (*models.VoteVariantTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.voteVariants[voteVariantID]; !ok {
+		return nil, apperrors.ErrVoteVariantNotFound
+	}
+
+	key := translationKey{id: voteVariantID, locale: locale}
+	translation, ok := r.voteVariantTranslations[key]
+	if !ok {
+		translation = models.VoteVariantTranslation{VoteVariantID: voteVariantID, Locale: locale, CreatedAt: updatedAt}
+	}
+	translation.Name = name
+	translation.UpdatedAt = updatedAt
+	r.voteVariantTranslations[key] = translation
+
+	return &translation, nil
+}
+
+func (r *Repository) DeleteVoteVariantTranslation(ctx context.Context, voteVariantID, locale string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := translationKey{id: voteVariantID, locale: locale}
+	if _, ok := r.voteVariantTranslations[key]; !ok {
+		return apperrors.ErrTranslationNotFound
+	}
+	delete(r.voteVariantTranslations, key)
+
+	return nil
+}
+
+// Все переводы вариантов voteVariantIDs, locale = "" - на все языки
+func (r *Repository) GetVoteVariantTranslations(ctx context.Context, voteVariantIDs []string, locale string) ([]*models.VoteVariantTranslation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var translations []*models.VoteVariantTranslation
+	for key, translation := range r.voteVariantTranslations {
+		if slices.Contains(voteVariantIDs, key.id) && (locale == "" || key.locale == locale) {
+			translations = append(translations, &translation)
+		}
+	}
+	slices.SortFunc(translations, func(a, b *models.VoteVariantTranslation) int {
+		return compareKeys(a.VoteVariantID, a.Locale, b.VoteVariantID, b.Locale)
+	})
+
+	return translations, nil
+}
+
+func compareKeys(aID, aLocale, bID, bLocale string) int {
+	if c := strings.Compare(aID, bID); c != 0 {
+		return c
+	}
+	return strings.Compare(aLocale, bLocale)
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r *Repository) CreateUser(ctx context.Context, id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error) {
+	pp := "internal/database/memory/repository/CreateUser"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; ok {
+		return nil, fmt.Errorf("%s: error: %w", pp, errDuplicateID)
+	}
+	if r.nicknameTaken(nickname, "") {
+		return nil, apperrors.ErrUserAlreadyExist
+	}
+
+	row := &userRow{
+		User: models.User{
+			ID:        id,
+			Nickname:  nickname,
+			Password:  password,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+			Version:   1,
+		},
+		seq: r.nextSeq(),
+	}
+	r.users[id] = row
+
+	user := row.User
+	return &user, nil
+}
+
+func (r *Repository) GetUsers(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rows []*userRow
+	for _, row := range r.users {
+		if row.deletedAt == nil {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *userRow) int {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.seq, b.seq)
+	})
+
+	var users []*models.User
+	for _, row := range page(rows, limit, offset) {
+		user := row.User
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+func (r *Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.users[id]
+	if !ok || row.deletedAt != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	user := row.User
+	return &user, nil
+}
+
+func (r *Repository) GetUserByNickname(ctx context.Context, nickname string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, row := range r.users {
+		if row.deletedAt == nil && row.Nickname == nickname {
+			user := row.User
+			return &user, nil
+		}
+	}
+
+	return nil, apperrors.ErrUserNotFound
+}
+
+func (r *Repository) UpdateUser(ctx context.Context, id, nickname string, version *int64, updatedAt time.Time) (*models.User, error) {
+	return r.PatchUser(ctx, id, &nickname, version, updatedAt)
+}
+
+func (r *Repository) UpdateUserPassword(ctx context.Context, id, password string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.users[id]
+	if !ok || row.deletedAt != nil {
+		return apperrors.ErrUserNotFound
+	}
+
+	row.Password = password
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	return nil
+}
+
+func (r *Repository) DeleteUser(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.users[id]
+	if !ok {
+		return apperrors.ErrUserNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	row.deletedAt = &deletedAt
+	row.Version++
+
+	return nil
+}
+
+func (r *Repository) PatchUser(ctx context.Context, id string, nickname *string, version *int64, updatedAt time.Time) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.users[id]
+	if !ok {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrUserNotFound); err != nil {
+		return nil, err
+	}
+	if nickname != nil && r.nicknameTaken(*nickname, id) {
+		return nil, apperrors.ErrUserAlreadyExist
+	}
+
+	if nickname != nil {
+		row.Nickname = *nickname
+	}
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	user := row.User
+	return &user, nil
+}
+
+func (r *Repository) RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.users[id]
+	if !ok || row.deletedAt == nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+	if r.nicknameTaken(row.Nickname, id) {
+		return nil, apperrors.ErrUserAlreadyExist
+	}
+
+	row.deletedAt = nil
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	user := row.User
+	return &user, nil
+}
+
+func (r *Repository) PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, row := range r.users {
+		if purgeable(row.deletedAt, deletedBefore) {
+			r.deleteUserCascade(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// Уникальность nickname только среди неудаленных, как idx_users_nickname_active
+func (r *Repository) nicknameTaken(nickname, exceptID string) bool {
+	for id, row := range r.users {
+		if id != exceptID && row.deletedAt == nil && row.Nickname == nickname {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/google/uuid"
+)
+
+func (r *Repository) CreateVote(ctx context.Context, id, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.votes[id]; ok {
+		return nil, apperrors.ErrVoteAlreadyExist
+	}
+	if err := r.checkVoteReferences(id, userID, voteVariantID); err != nil {
+		return nil, err
+	}
+
+	row := &voteRow{
+		Vote: models.Vote{
+			ID:        id,
+			UserID:    userID,
+			VariantID: voteVariantID,
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		},
+		seq: r.nextSeq(),
+	}
+	r.votes[id] = row
+	r.addVoteChange(row.Vote, "", createdAt)
+
+	vote := row.Vote
+	return &vote, nil
+}
+
+func (r *Repository) GetVote(ctx context.Context, id string) (*models.Vote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.votes[id]
+	if !ok {
+		return nil, apperrors.ErrVoteNotFound
+	}
+
+	vote := row.Vote
+	return &vote, nil
+}
+
+// Отзыв голоса остается в истории записью без NewVariantID
+func (r *Repository) DeleteVote(ctx context.Context, id string, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.votes[id]
+	if !ok {
+		return apperrors.ErrVoteNotFound
+	}
+	delete(r.votes, id)
+
+	withdrawn := row.Vote
+	withdrawn.VariantID = ""
+	r.addVoteChange(withdrawn, row.VariantID, deletedAt)
+
+	return nil
+}
+
+func (r *Repository) PatchVote(ctx context.Context, id string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.votes[id]
+	if !ok {
+		return nil, apperrors.ErrVoteNotFound
+	}
+
+	newUserID, newVoteVariantID := row.UserID, row.VariantID
+	if userID != nil {
+		newUserID = *userID
+	}
+	if voteVariantID != nil {
+		newVoteVariantID = *voteVariantID
+	}
+	if err := r.checkVoteReferences(id, newUserID, newVoteVariantID); err != nil {
+		return nil, err
+	}
+
+	oldVariantID := row.VariantID
+	row.UserID = newUserID
+	row.VariantID = newVoteVariantID
+	row.UpdatedAt = updatedAt
+	if row.VariantID != oldVariantID {
+		r.addVoteChange(row.Vote, oldVariantID, updatedAt)
+	}
+
+	vote := row.Vote
+	return &vote, nil
+}
+
+func (r *Repository) GetUserVotes(ctx context.Context, userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rows []*voteRow
+	for _, row := range r.votes {
+		if row.UserID != userID {
+			continue
+		}
+		if len(voteVariantsIDs) > 0 && !slices.Contains(voteVariantsIDs, row.VariantID) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b *voteRow) int {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.seq, b.seq)
+	})
+
+	var votes []*models.Vote
+	for _, row := range page(rows, limit, offset) {
+		vote := row.Vote
+		votes = append(votes, &vote)
+	}
+
+	return votes, nil
+}
+
+// Как и в postgres, пока не реализовано
+func (r *Repository) GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error) {
+	return nil, nil
+}
+
+// Удаленные варианты в итоги не входят, сортировка по числу голосов, затем по имени
+func (r *Repository) GetElectionResults(ctx context.Context, electionID string) ([]*models.VariantResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, row := range r.votes {
+		counts[row.VariantID]++
+	}
+
+	var results []*models.VariantResult
+	for _, row := range r.electionVoteVariants(electionID) {
+		if !r.voteVariantAlive(row) {
+			continue
+		}
+		results = append(results, &models.VariantResult{
+			VariantID: row.ID,
+			Name:      row.Name,
+			Votes:     counts[row.ID],
+		})
+	}
+	slices.SortStableFunc(results, func(a, b *models.VariantResult) int {
+		if a.Votes != b.Votes {
+			if a.Votes > b.Votes {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return results, nil
+}
+
+// Голоса за неудаленные варианты неудаленных выборов в порядке подачи
+func (r *Repository) GetElectionVotes(ctx context.Context, electionID string) ([]*models.Vote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rows []*voteRow
+	for _, row := range r.votes {
+		if voteVariant, ok := r.voteVariants[row.VariantID]; ok && voteVariant.ElectionID == electionID && r.voteVariantAlive(voteVariant) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *voteRow) int {
+		return newestFirst(b.CreatedAt, a.CreatedAt, b.seq, a.seq)
+	})
+
+	var votes []*models.Vote
+	for _, row := range rows {
+		vote := row.Vote
+		votes = append(votes, &vote)
+	}
+
+	return votes, nil
+}
+
+func (r *Repository) GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rows []voteChangeRow
+	for _, row := range r.voteHistory {
+		if row.VoteID == voteID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b voteChangeRow) int {
+		return newestFirst(b.ChangedAt, a.ChangedAt, b.seq, a.seq)
+	})
+
+	var changes []*models.VoteChange
+	for _, row := range rows {
+		change := row.VoteChange
+		changes = append(changes, &change)
+	}
+
+	return changes, nil
+}
+
+// Один голос пользователя на выборы и неудаленные родители голоса, порядок проверок как в postgres.
+// Вызывается под r.mu
+func (r *Repository) checkVoteReferences(id, userID, voteVariantID string) error {
+	for otherID, row := range r.votes {
+		if otherID == id || row.UserID != userID {
+			continue
+		}
+		if row.VariantID == voteVariantID || r.sameElection(row.VariantID, voteVariantID) {
+			return apperrors.ErrVoteAlreadyExist
+		}
+	}
+	if user, ok := r.users[userID]; !ok || user.deletedAt != nil {
+		return apperrors.ErrUserNotFound
+	}
+	voteVariant, ok := r.voteVariants[voteVariantID]
+	if !ok || voteVariant.deletedAt != nil {
+		return apperrors.ErrVoteVariantNotFound
+	}
+	if election, ok := r.elections[voteVariant.ElectionID]; !ok || election.deletedAt != nil {
+		return apperrors.ErrVoteVariantNotFound
+	}
+	return nil
+}
+
+// Вызывается под r.mu
+func (r *Repository) sameElection(voteVariantID, otherVoteVariantID string) bool {
+	voteVariant, ok := r.voteVariants[voteVariantID]
+	if !ok {
+		return false
+	}
+	other, ok := r.voteVariants[otherVoteVariantID]
+	return ok && voteVariant.ElectionID == other.ElectionID
+}
+
+// Вызывается под r.mu
+func (r *Repository) addVoteChange(vote models.Vote, oldVariantID string, changedAt time.Time) {
+	r.voteHistory = append(r.voteHistory, voteChangeRow{
+		VoteChange: models.VoteChange{
+			ID:           uuid.NewString(),
+			VoteID:       vote.ID,
+			UserID:       vote.UserID,
+			OldVariantID: oldVariantID,
+			NewVariantID: vote.VariantID,
+			ChangedAt:    changedAt,
+		},
+		seq: r.nextSeq(),
+	})
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r *Repository) CreateVoteVariant(ctx context.Context, id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error) {
+	pp := "internal/database/memory/repository/CreateVoteVariant"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.voteVariants[id]; ok {
+		return nil, fmt.Errorf("%s: error: %w", pp, errDuplicateID)
+	}
+	if election, ok := r.elections[electionID]; !ok || election.deletedAt != nil {
+		return nil, apperrors.ErrElectionNotFound
+	}
+
+	row := &voteVariantRow{
+		VoteVariant: models.VoteVariant{
+			ID:         id,
+			ElectionID: electionID,
+			Name:       name,
+			CreatedAt:  createdAt,
+			UpdatedAt:  updatedAt,
+			Version:    1,
+		},
+		seq: r.nextSeq(),
+	}
+	r.voteVariants[id] = row
+
+	voteVariant := row.VoteVariant
+	return &voteVariant, nil
+}
+
+// Варианты в порядке добавления
+func (r *Repository) GetVoteVariants(ctx context.Context, electionID string) ([]*models.VoteVariant, error) {
+	if electionID == "" {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rows := r.electionVoteVariants(electionID)
+	rows = slices.DeleteFunc(rows, func(row *voteVariantRow) bool {
+		return !r.voteVariantAlive(row)
+	})
+
+	var voteVariants []*models.VoteVariant
+	for _, row := range rows {
+		voteVariant := row.VoteVariant
+		voteVariants = append(voteVariants, &voteVariant)
+	}
+
+	return voteVariants, nil
+}
+
+func (r *Repository) GetVoteVariantIDs(ctx context.Context, electionID string) ([]string, error) {
+	if electionID == "" {
+		return nil, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ids []string
+	for _, row := range r.electionVoteVariants(electionID) {
+		if r.voteVariantAlive(row) {
+			ids = append(ids, row.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *Repository) GetVoteVariant(ctx context.Context, id string) (*models.VoteVariant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, ok := r.voteVariants[id]
+	if !ok || !r.voteVariantAlive(row) {
+		return nil, apperrors.ErrVoteVariantNotFound
+	}
+
+	voteVariant := row.VoteVariant
+	return &voteVariant, nil
+}
+
+func (r *Repository) DeleteVoteVariant(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.voteVariants[id]
+	if !ok {
+		return apperrors.ErrVoteVariantNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrVoteVariantNotFound); err != nil {
+		return err
+	}
+
+	row.deletedAt = &deletedAt
+	row.Version++
+
+	return nil
+}
+
+func (r *Repository) UpdateVoteVariant(ctx context.Context, id, name string, version *int64, updatedAt time.Time) (*models.VoteVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.voteVariants[id]
+	if !ok {
+		return nil, apperrors.ErrVoteVariantNotFound
+	}
+	if err := checkVersion(row.deletedAt, row.Version, version, apperrors.ErrVoteVariantNotFound); err != nil {
+		return nil, err
+	}
+
+	row.Name = name
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	voteVariant := row.VoteVariant
+	return &voteVariant, nil
+}
+
+func (r *Repository) RestoreVoteVariant(ctx context.Context, id string, updatedAt time.Time) (*models.VoteVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.voteVariants[id]
+	if !ok || row.deletedAt == nil {
+		return nil, apperrors.ErrVoteVariantNotFound
+	}
+
+	row.deletedAt = nil
+	row.UpdatedAt = updatedAt
+	row.Version++
+
+	voteVariant := row.VoteVariant
+	return &voteVariant, nil
+}
+
+func (r *Repository) PurgeVoteVariants(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, row := range r.voteVariants {
+		if purgeable(row.deletedAt, deletedBefore) {
+			r.deleteVoteVariantCascade(id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// Все варианты выборов, включая удаленные, в порядке добавления. Вызывается под r.mu
+func (r *Repository) electionVoteVariants(electionID string) []*voteVariantRow {
+	var rows []*voteVariantRow
+	for _, row := range r.voteVariants {
+		if row.ElectionID == electionID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *voteVariantRow) int {
+		return compareSeq(a.seq, b.seq)
+	})
+
+	return rows
+}
+
+// Вариант виден, пока не удалены ни он сам, ни его выборы
+func (r *Repository) voteVariantAlive(row *voteVariantRow) bool {
+	election, ok := r.elections[row.ElectionID]
+	return row.deletedAt == nil && ok && election.deletedAt == nil
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error {
+	pp := "internal/database/postgres/repository/CreateAuditEvent"
+
+	// Пустые actor_id и request_id храним как NULL
+	const query = `
+	INSERT INTO audit_events (id, actor_id, action, entity_type, entity_id, before, after, request_id, created_at)
+	VALUES ($1, NULLIF($2, '')::UUID, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)`
+
+	_, err := r.pool.Exec(ctx, query,
+		event.ID,
+		event.ActorID,
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		event.Before,
+		event.After,
+		event.RequestID,
+		event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) GetAuditEvents(ctx context.Context, limit, offset int, filter models.AuditFilter) ([]*models.AuditEvent, error) {
+	pp := "internal/database/postgres/repository/GetAuditEvents"
+
+	qb := squirrel.
+		Select("id", "COALESCE(actor_id::TEXT, '')", "action", "entity_type", "entity_id",
+			"before", "after", "COALESCE(request_id, '')", "created_at").
+		From("audit_events")
+	if filter.EntityType != "" {
+		qb = qb.Where(squirrel.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityID != "" {
+		qb = qb.Where(squirrel.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.ActorID != "" {
+		qb = qb.Where(squirrel.Eq{"actor_id": filter.ActorID})
+	}
+	if filter.From != nil {
+		qb = qb.Where(squirrel.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		qb = qb.Where(squirrel.LtOrEq{"created_at": *filter.To})
+	}
+
+	query, args, err := qb.
+		OrderBy("created_at DESC", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var events []*models.AuditEvent
+	for rows.Next() {
+		var event models.AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.ActorID,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityID,
+			&event.Before,
+			&event.After,
+			&event.RequestID,
+			&event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return events, nil
+}
@@ -2,46 +2,202 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/alonsoF100/golos/internal/config"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/jackc/pgx/v5/stdlib"
-	"github.com/pressly/goose/v3"
 )
 
+// replicas - необязательные пулы реплик для тяжелых запросов на чтение, см. replica.go.
+// replicaDownUntil хранит для каждой реплики момент (UnixNano), до которого она пропускается
 type Repository struct {
-	pool *pgxpool.Pool
+	pool             *pgxpool.Pool
+	replicas         []*pgxpool.Pool
+	replicaDownUntil []atomic.Int64
+	replicaCooldown  time.Duration
+	next             *atomic.Uint64
 }
 
-func New(pool *pgxpool.Pool) *Repository {
+func New(pool *pgxpool.Pool, replicas ...*pgxpool.Pool) *Repository {
 	return &Repository{
-		pool: pool,
+		pool:             pool,
+		replicas:         replicas,
+		replicaDownUntil: make([]atomic.Int64, len(replicas)),
+		replicaCooldown:  defaultReplicaCooldown,
+		next:             new(atomic.Uint64),
 	}
 }
 
-func NewPool(cfg *config.Config) (*pgxpool.Pool, error) {
-	poolConfig, err := pgxpool.ParseConfig(cfg.Database.ConStr())
+// Пауза после сбоя реплики, нулевое значение оставляет значение по умолчанию
+func (r *Repository) WithReplicaCooldown(cooldown time.Duration) *Repository {
+	if cooldown > 0 {
+		r.replicaCooldown = cooldown
+	}
+	return r
+}
+
+// Пул с настройками из конфига. База в docker может подниматься дольше приложения,
+// поэтому первое подключение повторяется с экспоненциальной паузой
+func NewPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(cfg.Database.ConStr(), cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO добавлять необходимые настройки pool а
-
-	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	err = pool.Ping(context.Background())
+	backoff := cfg.Database.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		err = pool.Ping(ctx)
+		if err == nil {
+			return pool, nil
+		}
+		if attempt >= cfg.Database.ConnectAttempts {
+			pool.Close()
+			return nil, fmt.Errorf("ping after %d attempts: %w", attempt, err)
+		}
+
+		slog.Warn("Database is not ready, retrying", "attempt", attempt, "retry_in", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			pool.Close()
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, cfg.Database.ConnectMaxBackoff)
+	}
+}
+
+// Пулы реплик создаются без ping: недоступная при старте реплика
+// не мешает запуску, запросы к ней уходят в primary
+func NewReplicaPools(ctx context.Context, cfg *config.Config) ([]*pgxpool.Pool, error) {
+	var pools []*pgxpool.Pool
+	for i, dsn := range cfg.Database.Replicas {
+		poolConfig, err := newPoolConfig(dsn.Value(), cfg)
+		if err != nil {
+			closePools(pools)
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		// Без таймаута запрос к недоступной реплике ждет TCP connect до отмены контекста
+		if cfg.Database.ReplicaConnectTimeout > 0 {
+			poolConfig.ConnConfig.ConnectTimeout = cfg.Database.ReplicaConnectTimeout
+		}
+
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			closePools(pools)
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+func closePools(pools []*pgxpool.Pool) {
+	for _, pool := range pools {
+		pool.Close()
+	}
+}
+
+func newPoolConfig(dsn string, cfg *config.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
+	applyPoolSettings(poolConfig, cfg.Database)
 
-	connConfig := poolConfig.ConnConfig
-	db := stdlib.OpenDB(*connConfig)
-	if err := goose.Up(db, cfg.Migration.Dir); err != nil {
-		return nil, err
+	if cfg.Tracing.Enabled {
+		poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+	}
+
+	return poolConfig, nil
+}
+
+// Нулевые значения не трогают настройки pgx по умолчанию
+func applyPoolSettings(poolConfig *pgxpool.Config, cfg config.DatabaseConfig) {
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	// Параметры сессии уходят в startup message каждого соединения
+	params := poolConfig.ConnConfig.RuntimeParams
+	if cfg.ApplicationName != "" {
+		params["application_name"] = cfg.ApplicationName
+	}
+	if cfg.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+}
+
+// Предикат оптимистичной блокировки, nil - обновление без проверки версии.
+// Мягко удаленные строки изменять нельзя, поэтому они тоже отсекаются здесь
+func withVersion(id string, version *int64) squirrel.Eq {
+	if version == nil {
+		return squirrel.Eq{"id": id, "deleted_at": nil}
+	}
+	return squirrel.Eq{"id": id, "version": *version, "deleted_at": nil}
+}
+
+// Различает отсутствующую строку и устаревшую версию после UPDATE/DELETE без результата
+func (r Repository) missingOrConflict(ctx context.Context, table, id string, version *int64, notFound error) error {
+	if version == nil {
+		return notFound
+	}
+
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)", table)
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("internal/database/postgres/repository/missingOrConflict: error: %w", err)
+	}
+	if exists {
+		return apperrors.ErrVersionConflict
+	}
+
+	return notFound
+}
+
+// Строка существует и не удалена мягко
+func (r Repository) exists(ctx context.Context, table, id string) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)", table)
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("internal/database/postgres/repository/exists: error: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Жесткое удаление строк, мягко удаленных раньше before
+func (r Repository) purgeDeleted(ctx context.Context, table string, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < $1", table)
+
+	row, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("internal/database/postgres/repository/purgeDeleted: error: %w", err)
 	}
 
-	return pool, nil
+	return row.RowsAffected(), nil
 }
@@ -13,25 +13,30 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (r Repository) CreateElection(id, userID, name string, description string, updatedAt time.Time, createdAt time.Time) (*models.Election, error) {
+func (r Repository) CreateElection(ctx context.Context, id, userID, name string, description string, votePolicy string, createdAt time.Time, updatedAt time.Time) (*models.Election, error) {
 	pp := "internal/database/postgres/repository/CreateElection"
 
+	// Мягко удаленный пользователь проходит проверку внешнего ключа, поэтому строка вставляется только для живого
 	const query = `
-	INSERT INTO elections (id, user_id, name, description, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6)
-	RETURNING id, user_id, name, description, created_at, updated_at;`
+	INSERT INTO elections (id, user_id, name, description, vote_policy, created_at, updated_at)
+	SELECT $1, u.id, $3, $4, $5, $6, $7 FROM users u
+	WHERE u.id = $2 AND u.deleted_at IS NULL
+	RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version;`
 
 	var election models.Election
-	err := r.pool.QueryRow(context.Background(), query, id, userID, name, description, createdAt, updatedAt).Scan(
+	err := r.pool.QueryRow(ctx, query, id, userID, name, description, votePolicy, createdAt, updatedAt).Scan(
 		&election.ID,
 		&election.UserID,
 		&election.Name,
 		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
 		&election.CreatedAt,
-		&election.UpdatedAt)
+		&election.UpdatedAt,
+		&election.Version)
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "23503" {
 			return nil, apperrors.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
@@ -40,17 +45,31 @@ func (r Repository) CreateElection(id, userID, name string, description string,
 	return &election, nil
 }
 
-func (r Repository) GetElections(limit, offset int, userID string) ([]*models.Election, error) {
+func (r Repository) GetElections(ctx context.Context, limit, offset int, filter models.ElectionFilter) ([]*models.Election, error) {
 	pp := "internal/database/postgres/repository/GetElections"
 
 	qb := squirrel.
-		Select("id", "user_id", "name", "description", "created_at", "updated_at").
-		From("elections")
-	if userID != "" {
-		qb = qb.Where(squirrel.Eq{"user_id": userID})
+		Select("id", "user_id", "name", "description", "status", "vote_policy", "created_at", "updated_at", "version").
+		From("elections").
+		Where(squirrel.Eq{"deleted_at": nil})
+	if filter.UserID != "" {
+		qb = qb.Where(squirrel.Eq{"user_id": filter.UserID})
+	}
+	if filter.Query != "" {
+		qb = qb.Where("search_vector @@ plainto_tsquery('simple', ?)", filter.Query)
+	}
+	if filter.Status != "" {
+		qb = qb.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if filter.CreatedAfter != nil {
+		qb = qb.Where(squirrel.GtOrEq{"created_at": *filter.CreatedAfter})
 	}
+	if filter.CreatedBefore != nil {
+		qb = qb.Where(squirrel.LtOrEq{"created_at": *filter.CreatedBefore})
+	}
+	qb = orderElections(qb, filter)
+
 	query, args, err := qb.
-		OrderBy("created_at DESC").
 		Limit(uint64(limit)).
 		Offset(uint64(offset)).
 		PlaceholderFormat(squirrel.Dollar).
@@ -59,51 +78,46 @@ func (r Repository) GetElections(limit, offset int, userID string) ([]*models.El
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
-	rows, err := r.pool.Query(context.Background(), query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("%s: error: %w", pp, err)
-	}
-	defer rows.Close()
-
-	var elections []*models.Election
-	for rows.Next() {
+	elections, err := readRows(ctx, r, func(row pgx.CollectableRow) (*models.Election, error) {
 		var election models.Election
-		err := rows.Scan(
+		err := row.Scan(
 			&election.ID,
 			&election.UserID,
 			&election.Name,
 			&election.Description,
+			&election.Status,
+			&election.VotePolicy,
 			&election.CreatedAt,
-			&election.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("%s: error: %w", pp, err)
-		}
-
-		elections = append(elections, &election)
-	}
-	if err := rows.Err(); err != nil {
+			&election.UpdatedAt,
+			&election.Version)
+		return &election, err
+	}, query, args...)
+	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	return elections, nil
 }
 
-func (r Repository) GetElection(id string) (*models.Election, error) {
+func (r Repository) GetElection(ctx context.Context, id string) (*models.Election, error) {
 	pp := "internal/database/postgres/repository/GetElection"
 
 	const query = `
-	SELECT id, user_id, name, description, created_at, updated_at
+	SELECT id, user_id, name, description, status, vote_policy, created_at, updated_at, version
 	FROM elections
-	WHERE id = $1`
+	WHERE id = $1 AND deleted_at IS NULL`
 
 	var election models.Election
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&election.ID,
 		&election.UserID,
 		&election.Name,
 		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
 		&election.CreatedAt,
-		&election.UpdatedAt)
+		&election.UpdatedAt,
+		&election.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrElectionNotFound
@@ -114,33 +128,42 @@ func (r Repository) GetElection(id string) (*models.Election, error) {
 	return &election, nil
 }
 
-func (r Repository) DeleteElection(id string) error {
+func (r Repository) DeleteElection(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
 	pp := "internal/database/postgres/repository/DeleteElection"
 
-	const query = `
-	DELETE FROM elections
-	WHERE id = $1`
+	query, args, err := squirrel.Update("elections").
+		Set("deleted_at", deletedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
 
-	row, err := r.pool.Exec(context.Background(), query, id)
+	row, err := r.pool.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	if row.RowsAffected() == 0 {
-		return apperrors.ErrElectionNotFound
+		return r.missingOrConflict(ctx, "elections", id, version, apperrors.ErrElectionNotFound)
 	}
 
 	return nil
 }
 
-func (r Repository) PatchElection(id string, userID, name, description *string, updatedAt time.Time) (*models.Election, error) {
+func (r Repository) PatchElection(ctx context.Context, id string, userID, name, description, status, votePolicy *string, version *int64, updatedAt time.Time) (*models.Election, error) {
 	pp := "internal/database/postgres/repository/PatchElection"
 
 	qb := squirrel.Update("elections").
 		Set("updated_at", updatedAt).
-		Where(squirrel.Eq{"id": id})
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version))
+	// Новый владелец должен существовать и не быть мягко удаленным
 	if userID != nil {
-		qb = qb.Set("user_id", *userID)
+		qb = qb.Set("user_id", *userID).
+			Where("EXISTS (SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL)", *userID)
 	}
 	if name != nil {
 		qb = qb.Set("name", *name)
@@ -148,25 +171,43 @@ func (r Repository) PatchElection(id string, userID, name, description *string,
 	if description != nil {
 		qb = qb.Set("description", *description)
 	}
+	if status != nil {
+		qb = qb.Set("status", *status)
+	}
+	if votePolicy != nil {
+		qb = qb.Set("vote_policy", *votePolicy)
+	}
 	query, args, err := qb.
 		PlaceholderFormat(squirrel.Dollar).
-		Suffix("RETURNING id, user_id, name, description, created_at, updated_at").
+		Suffix("RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	var election models.Election
-	err = r.pool.QueryRow(context.Background(), query, args...).Scan(
+	err = r.pool.QueryRow(ctx, query, args...).Scan(
 		&election.ID,
 		&election.UserID,
 		&election.Name,
 		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
 		&election.CreatedAt,
-		&election.UpdatedAt)
+		&election.UpdatedAt,
+		&election.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, apperrors.ErrElectionNotFound
+			if userID != nil {
+				exists, err := r.exists(ctx, "users", *userID)
+				if err != nil {
+					return nil, fmt.Errorf("%s: error: %w", pp, err)
+				}
+				if !exists {
+					return nil, apperrors.ErrUserNotFound
+				}
+			}
+			return nil, r.missingOrConflict(ctx, "elections", id, version, apperrors.ErrElectionNotFound)
 		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
@@ -177,3 +218,62 @@ func (r Repository) PatchElection(id string, userID, name, description *string,
 
 	return &election, nil
 }
+
+func (r Repository) RestoreElection(ctx context.Context, id string, updatedAt time.Time) (*models.Election, error) {
+	pp := "internal/database/postgres/repository/RestoreElection"
+
+	const query = `
+	UPDATE elections
+	SET deleted_at = NULL, updated_at = $1, version = version + 1
+	WHERE id = $2 AND deleted_at IS NOT NULL
+	RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version`
+
+	var election models.Election
+	err := r.pool.QueryRow(ctx, query, updatedAt, id).Scan(
+		&election.ID,
+		&election.UserID,
+		&election.Name,
+		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
+		&election.CreatedAt,
+		&election.UpdatedAt,
+		&election.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrElectionNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &election, nil
+}
+
+func (r Repository) PurgeElections(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "elections", deletedBefore)
+}
+
+// Сортировка по белому списку полей, по умолчанию сначала новые
+func orderElections(qb squirrel.SelectBuilder, filter models.ElectionFilter) squirrel.SelectBuilder {
+	direction := "DESC"
+	if filter.SortOrder == models.SortOrderAsc {
+		direction = "ASC"
+	}
+
+	switch filter.SortBy {
+	case models.ElectionSortRelevance:
+		if filter.Query == "" {
+			return qb.OrderBy("created_at " + direction)
+		}
+		return qb.OrderByClause(
+			"ts_rank(search_vector, plainto_tsquery('simple', ?)) "+direction,
+			filter.Query,
+		).OrderBy("created_at DESC")
+	case models.ElectionSortName:
+		return qb.OrderBy("name "+direction, "created_at DESC")
+	case models.ElectionSortUpdatedAt:
+		return qb.OrderBy("updated_at " + direction)
+	default:
+		return qb.OrderBy("created_at " + direction)
+	}
+}
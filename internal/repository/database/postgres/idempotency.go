@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Занимает ключ, если его нет или он уже истек, false - ключ занят другим запросом
+func (r Repository) ClaimIdempotencyKey(ctx context.Context, key, requestHash string, createdAt time.Time, expiresAt time.Time) (bool, error) {
+	pp := "internal/database/postgres/repository/ClaimIdempotencyKey"
+
+	const query = `
+	INSERT INTO idempotency_keys (key, request_hash, status_code, body, created_at, expires_at)
+	VALUES ($1, $2, 0, NULL, $3, $4)
+	ON CONFLICT (key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash,
+		status_code = 0,
+		etag = '',
+		location = '',
+		body = NULL,
+		created_at = EXCLUDED.created_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at < EXCLUDED.created_at`
+
+	row, err := r.pool.Exec(ctx, query, key, requestHash, createdAt, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return row.RowsAffected() == 1, nil
+}
+
+func (r Repository) GetIdempotencyRecord(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	pp := "internal/database/postgres/repository/GetIdempotencyRecord"
+
+	const query = `
+	SELECT key, request_hash, status_code, etag, location, body, created_at, expires_at FROM idempotency_keys
+	WHERE key = $1`
+
+	var record models.IdempotencyRecord
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ETag,
+		&record.Location,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrIdempotencyKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &record, nil
+}
+
+func (r Repository) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error {
+	pp := "internal/database/postgres/repository/SaveIdempotencyResponse"
+
+	const query = `
+	UPDATE idempotency_keys
+	SET status_code = $1, etag = $2, location = $3, body = $4
+	WHERE key = $5`
+
+	row, err := r.pool.Exec(ctx, query, statusCode, etag, location, body, key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return apperrors.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	pp := "internal/database/postgres/repository/DeleteIdempotencyKey"
+
+	const query = `
+	DELETE FROM idempotency_keys
+	WHERE key = $1`
+
+	_, err := r.pool.Exec(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	pp := "internal/database/postgres/repository/DeleteExpiredIdempotencyKeys"
+
+	const query = `
+	DELETE FROM idempotency_keys
+	WHERE expires_at < $1`
+
+	row, err := r.pool.Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return row.RowsAffected(), nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	_ "github.com/alonsoF100/golos/migrations/postgres"
+)
+
+// Миграции зарегистрированы в migrations/postgres через init, файлы на диске не нужны.
+// Advisory lock на сессию не дает нескольким репликам мигрировать одновременно
+func NewMigrator(pool *pgxpool.Pool) (*goose.Provider, error) {
+	pp := "internal/database/postgres/NewMigrator"
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, stdlib.OpenDBFromPool(pool), nil,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return provider, nil
+}
+
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	results, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("internal/database/postgres/Migrate: error: %w", err)
+	}
+	for _, result := range results {
+		slog.Info("Migration applied", "version", result.Source.Version, "duration", result.Duration)
+	}
+
+	return nil
+}
+
+// Последняя версия среди зарегистрированных миграций, база не запрашивается
+func LatestMigration(pool *pgxpool.Pool) (int64, error) {
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		return 0, err
+	}
+	defer migrator.Close()
+
+	sources := migrator.ListSources()
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	return sources[len(sources)-1].Version, nil
+}
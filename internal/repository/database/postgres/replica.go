@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Реплика после сбоя пропускается, чтобы каждый запрос не ждал ее таймаута
+const defaultReplicaCooldown = 30 * time.Second
+
+// На реплику уходят только списки и итоги (GetUsers, GetElections, GetVoteVariants,
+// GetElectionResults), где отставание на доли секунды допустимо. Голоса пользователя
+// (GetUserVotes, GetVoteVariantIDs) и все, что читается перед записью, остаются в primary:
+// сразу после голосования избиратель должен видеть свой голос.
+// Строки читаются целиком до возврата: ошибки сервера вроде конфликта с восстановлением
+// pgx часто отдает уже из rows.Next, и тогда запрос тоже повторяется на primary
+func readRows[T any](ctx context.Context, r Repository, scan pgx.RowToFunc[T], query string, args ...any) ([]T, error) {
+	i, replica := r.replica()
+	if replica == nil {
+		items, scanErr, err := collectRows(ctx, r.pool, scan, query, args...)
+		return items, errors.Join(scanErr, err)
+	}
+
+	items, scanErr, err := collectRows(ctx, replica, scan, query, args...)
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	if err == nil || !replicaUnavailable(ctx, err) {
+		return items, err
+	}
+
+	r.replicaDownUntil[i].Store(time.Now().Add(r.replicaCooldown).UnixNano())
+	slog.WarnContext(ctx, "Replica is unavailable, falling back to primary", "replica", i, "cooldown", r.replicaCooldown, "error", err)
+
+	items, scanErr, err = collectRows(ctx, r.pool, scan, query, args...)
+	return items, errors.Join(scanErr, err)
+}
+
+// Ошибка scan - ошибка самого запроса и повторилась бы на любом узле,
+// поэтому она возвращается отдельно от ошибок запроса и чтения строк
+func collectRows[T any](ctx context.Context, pool *pgxpool.Pool, scan pgx.RowToFunc[T], query string, args ...any) (items []T, scanErr, err error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err, nil
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return items, nil, nil
+}
+
+// Реплики выбираются по кругу, упавшие до конца паузы пропускаются
+func (r Repository) replica() (int, *pgxpool.Pool) {
+	if len(r.replicas) == 0 {
+		return -1, nil
+	}
+
+	now := time.Now().UnixNano()
+	start := r.next.Add(1)
+	for offset := range uint64(len(r.replicas)) {
+		i := int((start + offset) % uint64(len(r.replicas)))
+		if r.replicaDownUntil[i].Load() <= now {
+			return i, r.replicas[i]
+		}
+	}
+	return -1, nil
+}
+
+// Ошибки запроса повторились бы и на primary, а сетевые ошибки, старт реплики
+// и конфликт с восстановлением - нет
+func replicaUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return true
+	}
+
+	switch pgErr.Code {
+	case "40001", "57P01", "57P02", "57P03":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func (r Repository) GetUserRole(ctx context.Context, userID string) (string, error) {
+	pp := "internal/database/postgres/repository/GetUserRole"
+
+	const query = `
+	SELECT role FROM user_roles
+	WHERE user_id = $1`
+
+	var role string
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.RoleUser, nil
+		}
+		return "", fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return role, nil
+}
+
+func (r Repository) SetUserRole(ctx context.Context, userID, role string, updatedAt time.Time) error {
+	pp := "internal/database/postgres/repository/SetUserRole"
+
+	const query = `
+	INSERT INTO user_roles (user_id, role, created_at, updated_at)
+	VALUES ($1, $2, $3, $3)
+	ON CONFLICT (user_id) DO UPDATE
+	SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.pool.Exec(ctx, query, userID, role, updatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperrors.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
@@ -13,21 +13,22 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (r Repository) CreateUser(id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error) {
+func (r Repository) CreateUser(ctx context.Context, id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error) {
 	pp := "internal/database/postgres/repository/CreatetUser"
 
 	const query = `
 	INSERT INTO users (id, nickname, password, created_at, updated_at)
 	VALUES ($1, $2, $3, $4, $5)
-	RETURNING id, nickname, password, created_at, updated_at`
+	RETURNING id, nickname, password, created_at, updated_at, version`
 
 	var user models.User
-	err := r.pool.QueryRow(context.Background(), query, id, nickname, password, createdAt, updatedAt).Scan(
+	err := r.pool.QueryRow(ctx, query, id, nickname, password, createdAt, updatedAt).Scan(
 		&user.ID,
 		&user.Nickname,
 		&user.Password,
 		&user.CreatedAt,
-		&user.UpdatedAt)
+		&user.UpdatedAt,
+		&user.Version)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -39,12 +40,13 @@ func (r Repository) CreateUser(id, nickname, password string, createdAt time.Tim
 	return &user, nil
 }
 
-func (r Repository) GetUsers(limit, offset int) ([]*models.User, error) {
+func (r Repository) GetUsers(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	pp := "internal/database/postgres/repository/GetUsers"
 
 	query, args, err := squirrel.
-		Select("id", "nickname", "password", "created_at", "updated_at").
+		Select("id", "nickname", "password", "created_at", "updated_at", "version").
 		From("users").
+		Where(squirrel.Eq{"deleted_at": nil}).
 		OrderBy("created_at DESC").
 		Limit(uint64(limit)).
 		Offset(uint64(offset)).
@@ -54,50 +56,40 @@ func (r Repository) GetUsers(limit, offset int) ([]*models.User, error) {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
-	rows, err := r.pool.Query(context.Background(), query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("%s: error: %w", pp, err)
-	}
-	defer rows.Close()
-
-	var users []*models.User
-	for rows.Next() {
+	users, err := readRows(ctx, r, func(row pgx.CollectableRow) (*models.User, error) {
 		var user models.User
 
-		err := rows.Scan(
+		err := row.Scan(
 			&user.ID,
 			&user.Nickname,
 			&user.Password,
 			&user.CreatedAt,
-			&user.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("%s: error: %w", pp, err)
-		}
-
-		users = append(users, &user)
-	}
-
-	if err := rows.Err(); err != nil {
+			&user.UpdatedAt,
+			&user.Version)
+		return &user, err
+	}, query, args...)
+	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	return users, nil
 }
 
-func (r Repository) GetUser(id string) (*models.User, error) {
+func (r Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
 	pp := "internal/database/postgres/repository/GetUser"
 
 	const query = `
-	SELECT id, nickname, password, created_at, updated_at FROM users
-	WHERE id = $1`
+	SELECT id, nickname, password, created_at, updated_at, version FROM users
+	WHERE id = $1 AND deleted_at IS NULL`
 
 	var user models.User
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Nickname,
 		&user.Password,
 		&user.CreatedAt,
-		&user.UpdatedAt)
+		&user.UpdatedAt,
+		&user.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrUserNotFound
@@ -108,25 +100,36 @@ func (r Repository) GetUser(id string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r Repository) UpdateUser(id, nickname, password string, updatedAt time.Time) (*models.User, error) {
+func (r Repository) UpdateUser(ctx context.Context, id, nickname string, version *int64, updatedAt time.Time) (*models.User, error) {
 	pp := "internal/database/postgres/repository/UpdateUser"
 
-	const query = `
-	UPDATE users
-	SET nickname = $1, password = $2, updated_at = $3
-	WHERE id = $4
-	RETURNING id, nickname, password, created_at, updated_at`
+	query, args, err := squirrel.Update("users").
+		Set("nickname", nickname).
+		Set("updated_at", updatedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		PlaceholderFormat(squirrel.Dollar).
+		Suffix("RETURNING id, nickname, password, created_at, updated_at, version").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
 
 	var user models.User
-	err := r.pool.QueryRow(context.Background(), query, nickname, password, updatedAt, id).Scan(
+	err = r.pool.QueryRow(ctx, query, args...).Scan(
 		&user.ID,
 		&user.Nickname,
 		&user.Password,
 		&user.CreatedAt,
-		&user.UpdatedAt)
+		&user.UpdatedAt,
+		&user.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, apperrors.ErrUserNotFound
+			return nil, r.missingOrConflict(ctx, "users", id, version, apperrors.ErrUserNotFound)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.ErrUserAlreadyExist
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
@@ -134,14 +137,15 @@ func (r Repository) UpdateUser(id, nickname, password string, updatedAt time.Tim
 	return &user, nil
 }
 
-func (r Repository) DeleteUser(id string) error {
-	pp := "internal/database/postgres/repository/DeleteUser"
+func (r Repository) UpdateUserPassword(ctx context.Context, id, password string, updatedAt time.Time) error {
+	pp := "internal/database/postgres/repository/UpdateUserPassword"
 
 	const query = `
-	DELETE FROM users
-	WHERE id = $1`
+	UPDATE users
+	SET password = $1, updated_at = $2, version = version + 1
+	WHERE id = $3 AND deleted_at IS NULL`
 
-	row, err := r.pool.Exec(context.Background(), query, id)
+	row, err := r.pool.Exec(ctx, query, password, updatedAt, id)
 	if err != nil {
 		return fmt.Errorf("%s: error: %w", pp, err)
 	}
@@ -153,36 +157,64 @@ func (r Repository) DeleteUser(id string) error {
 	return nil
 }
 
-func (r Repository) PatchUser(id string, nickname, password *string, updatedAt time.Time) (*models.User, error) {
+func (r Repository) DeleteUser(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	pp := "internal/database/postgres/repository/DeleteUser"
+
+	query, args, err := squirrel.Update("users").
+		Set("deleted_at", deletedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	row, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if row.RowsAffected() == 0 {
+		return r.missingOrConflict(ctx, "users", id, version, apperrors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r Repository) PatchUser(ctx context.Context, id string, nickname *string, version *int64, updatedAt time.Time) (*models.User, error) {
 	pp := "internal/database/postgres/repository/PatchUser"
 
 	qb := squirrel.Update("users").
 		Set("updated_at", updatedAt).
-		Where(squirrel.Eq{"id": id})
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version))
 	if nickname != nil {
 		qb = qb.Set("nickname", *nickname)
 	}
-	if password != nil {
-		qb = qb.Set("password", *password)
-	}
 	query, args, err := qb.
 		PlaceholderFormat(squirrel.Dollar).
-		Suffix("RETURNING id, nickname, password, created_at, updated_at").
+		Suffix("RETURNING id, nickname, password, created_at, updated_at, version").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	var user models.User
-	err = r.pool.QueryRow(context.Background(), query, args...).Scan(
+	err = r.pool.QueryRow(ctx, query, args...).Scan(
 		&user.ID,
 		&user.Nickname,
 		&user.Password,
 		&user.CreatedAt,
-		&user.UpdatedAt)
+		&user.UpdatedAt,
+		&user.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, apperrors.ErrUserNotFound
+			return nil, r.missingOrConflict(ctx, "users", id, version, apperrors.ErrUserNotFound)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.ErrUserAlreadyExist
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
@@ -190,26 +222,62 @@ func (r Repository) PatchUser(id string, nickname, password *string, updatedAt t
 	return &user, nil
 }
 
-func (r Repository) GetUserByNickname(nickname string) (*models.User, error) {
+func (r Repository) GetUserByNickname(ctx context.Context, nickname string) (*models.User, error) {
 	pp := "internal/database/postgres/repository/GetUser"
 
 	const query = `
-	SELECT id, nickname, password, created_at, updated_at FROM users
-	WHERE nickname = $1`
+	SELECT id, nickname, password, created_at, updated_at, version FROM users
+	WHERE nickname = $1 AND deleted_at IS NULL`
+
+	var user models.User
+	err := r.pool.QueryRow(ctx, query, nickname).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error) {
+	pp := "internal/database/postgres/repository/RestoreUser"
+
+	const query = `
+	UPDATE users
+	SET deleted_at = NULL, updated_at = $1, version = version + 1
+	WHERE id = $2 AND deleted_at IS NOT NULL
+	RETURNING id, nickname, password, created_at, updated_at, version`
 
 	var user models.User
-	err := r.pool.QueryRow(context.Background(), query, nickname).Scan(
+	err := r.pool.QueryRow(ctx, query, updatedAt, id).Scan(
 		&user.ID,
 		&user.Nickname,
 		&user.Password,
 		&user.CreatedAt,
-		&user.UpdatedAt)
+		&user.UpdatedAt,
+		&user.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrUserNotFound
 		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.ErrUserAlreadyExist
+		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	return &user, nil
 }
+
+func (r Repository) PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "users", deletedBefore)
+}
@@ -13,33 +13,59 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (r Repository) CreateVote(uuid, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error) {
+func (r Repository) CreateVote(ctx context.Context, uuid, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error) {
 	pp := "internal/database/postgres/repository/CreateVote"
 
+	// Голос за мягко удаленный вариант или от мягко удаленного пользователя не вставляется.
+	// election_id берется из варианта: уникальный индекс (user_id, election_id) - один голос на выборы
 	const query = `
-	INSERT INTO votes (id, user_id, variant_id, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO votes (id, user_id, variant_id, election_id, created_at, updated_at)
+	SELECT $1, u.id, vv.id, vv.election_id, $4, $5
+	FROM users u, vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE u.id = $2 AND u.deleted_at IS NULL
+		AND vv.id = $3 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
 	RETURNING id, user_id, variant_id, created_at, updated_at`
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer tx.Rollback(ctx)
+
 	var vote models.Vote
-	err := r.pool.QueryRow(context.Background(), query, uuid, userID, voteVariantID, createdAt, updatedAt).Scan(
+	err = tx.QueryRow(ctx, query, uuid, userID, voteVariantID, createdAt, updatedAt).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.VariantID,
 		&vote.CreatedAt,
 		&vote.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missingVoteReference(ctx, tx, userID)
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return nil, errors.Join(apperrors.ErrUserNotFound, apperrors.ErrVoteVariantNotFound)
+			return nil, voteReferenceError(pgErr)
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.ErrVoteAlreadyExist
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
+	if err := insertVoteChange(ctx, tx, vote.ID, vote.UserID, nil, vote.VariantID, createdAt); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
 	return &vote, nil
 }
 
-func (r Repository) GetVote(uuid string) (*models.Vote, error) {
+func (r Repository) GetVote(ctx context.Context, uuid string) (*models.Vote, error) {
 	pp := "internal/database/postgres/repository/GetVote"
 
 	const query = `
@@ -47,7 +73,7 @@ func (r Repository) GetVote(uuid string) (*models.Vote, error) {
 	WHERE id = $1`
 
 	var vote models.Vote
-	err := r.pool.QueryRow(context.Background(), query, uuid).Scan(
+	err := r.pool.QueryRow(ctx, query, uuid).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.VariantID,
@@ -63,14 +89,20 @@ func (r Repository) GetVote(uuid string) (*models.Vote, error) {
 	return &vote, nil
 }
 
-func (r Repository) DeleteVote(uuid string) error {
+// Отзыв голоса остается в истории записью без new_variant_id
+func (r Repository) DeleteVote(ctx context.Context, uuid string, deletedAt time.Time) error {
 	pp := "internal/database/postgres/repository/DeleteVote"
 
 	const query = `
-	DELETE FROM votes
-	WHERE id = $1`
+	WITH deleted AS (
+		DELETE FROM votes
+		WHERE id = $1
+		RETURNING id, user_id, variant_id
+	)
+	INSERT INTO vote_history (vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+	SELECT id, user_id, variant_id, NULL, $2 FROM deleted`
 
-	row, err := r.pool.Exec(context.Background(), query, uuid)
+	row, err := r.pool.Exec(ctx, query, uuid, deletedAt)
 	if err != nil {
 		return fmt.Errorf("%s: error: %w", pp, err)
 	}
@@ -82,9 +114,30 @@ func (r Repository) DeleteVote(uuid string) error {
 	return nil
 }
 
-func (r Repository) PatchVote(uuid string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error) {
+func (r Repository) PatchVote(ctx context.Context, uuid string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error) {
 	pp := "internal/database/postgres/repository/PatchVote"
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем строку, чтобы параллельные изменения не потеряли запись в истории
+	const lockQuery = `
+	SELECT variant_id FROM votes
+	WHERE id = $1
+	FOR UPDATE`
+
+	var oldVariantID string
+	err = tx.QueryRow(ctx, lockQuery, uuid).Scan(&oldVariantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperrors.ErrVoteNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
 	qb := squirrel.Update("votes").
 		Set("updated_at", updatedAt).
 		Where(squirrel.Eq{"id": uuid})
@@ -92,18 +145,21 @@ func (r Repository) PatchVote(uuid string, userID, voteVariantID *string, update
 		qb = qb.Set("user_id", *userID)
 	}
 	if voteVariantID != nil {
-		qb = qb.Set("variant_id", *voteVariantID)
+		// Неизвестный вариант оставляет прежние выборы, ошибку тогда дает внешний ключ variant_id
+		qb = qb.
+			Set("variant_id", *voteVariantID).
+			Set("election_id", squirrel.Expr("COALESCE((SELECT election_id FROM vote_variants WHERE id = ?), election_id)", *voteVariantID))
 	}
 	query, args, err := qb.
 		PlaceholderFormat(squirrel.Dollar).
-		Suffix("RETURNING id, user_id, variant_id, created_ad, updated_at").
+		Suffix("RETURNING id, user_id, variant_id, created_at, updated_at").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	var vote models.Vote
-	err = r.pool.QueryRow(context.Background(), query, args...).Scan(
+	err = tx.QueryRow(ctx, query, args...).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.VariantID,
@@ -115,15 +171,28 @@ func (r Repository) PatchVote(uuid string, userID, voteVariantID *string, update
 		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
-			return nil, errors.Join(apperrors.ErrUserNotFound, apperrors.ErrVoteVariantNotFound)
+			return nil, voteReferenceError(pgErr)
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, apperrors.ErrVoteAlreadyExist
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
+	if vote.VariantID != oldVariantID {
+		if err := insertVoteChange(ctx, tx, vote.ID, vote.UserID, &oldVariantID, vote.VariantID, updatedAt); err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
 	return &vote, nil
 }
 
-func (r Repository) GetUserVotes(userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error) {
+func (r Repository) GetUserVotes(ctx context.Context, userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error) {
 	pp := "internal/database/postgres/repository/GetUserVotes"
 
 	qb := squirrel.Select("id", "user_id", "variant_id", "created_at", "updated_at").
@@ -143,7 +212,7 @@ func (r Repository) GetUserVotes(userID string, voteVariantsIDs []string, limit,
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
-	rows, err := r.pool.Query(context.Background(), query, args...)
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
@@ -174,6 +243,147 @@ func (r Repository) GetUserVotes(userID string, voteVariantsIDs []string, limit,
 	return votes, nil
 }
 
-func (r Repository) GetVariantVotes(voteVariantID string) ([]*models.Vote, error) {
+func (r Repository) GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error) {
 	return nil, nil
 }
+
+// Варианты без голосов тоже попадают в итоги с нулем
+func (r Repository) GetElectionResults(ctx context.Context, electionID string) ([]*models.VariantResult, error) {
+	pp := "internal/database/postgres/repository/GetElectionResults"
+
+	const query = `
+	SELECT vv.id, vv.name, COUNT(v.id)
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	LEFT JOIN votes v ON v.variant_id = vv.id
+	WHERE vv.election_id = $1 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
+	GROUP BY vv.id, vv.name
+	ORDER BY COUNT(v.id) DESC, vv.name`
+
+	results, err := readRows(ctx, r, func(row pgx.CollectableRow) (*models.VariantResult, error) {
+		var result models.VariantResult
+		err := row.Scan(&result.VariantID, &result.Name, &result.Votes)
+		return &result, err
+	}, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return results, nil
+}
+
+func (r Repository) GetElectionVotes(ctx context.Context, electionID string) ([]*models.Vote, error) {
+	pp := "internal/database/postgres/repository/GetElectionVotes"
+
+	const query = `
+	SELECT v.id, v.user_id, v.variant_id, v.created_at, v.updated_at
+	FROM votes v
+	JOIN vote_variants vv ON vv.id = v.variant_id
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = $1 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
+	ORDER BY v.created_at`
+
+	rows, err := r.pool.Query(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var votes []*models.Vote
+	for rows.Next() {
+		var vote models.Vote
+
+		err := rows.Scan(
+			&vote.ID,
+			&vote.UserID,
+			&vote.VariantID,
+			&vote.CreatedAt,
+			&vote.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		votes = append(votes, &vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return votes, nil
+}
+
+func (r Repository) GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error) {
+	pp := "internal/database/postgres/repository/GetVoteHistory"
+
+	const query = `
+	SELECT id, vote_id, user_id, COALESCE(old_variant_id::TEXT, ''), COALESCE(new_variant_id::TEXT, ''), changed_at
+	FROM vote_history
+	WHERE vote_id = $1
+	ORDER BY changed_at, id`
+
+	rows, err := r.pool.Query(ctx, query, voteID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var changes []*models.VoteChange
+	for rows.Next() {
+		var change models.VoteChange
+		err := rows.Scan(
+			&change.ID,
+			&change.VoteID,
+			&change.UserID,
+			&change.OldVariantID,
+			&change.NewVariantID,
+			&change.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return changes, nil
+}
+
+// По имени внешнего ключа понятно, какой из связанных объектов не найден
+func voteReferenceError(pgErr *pgconn.PgError) error {
+	if pgErr.ConstraintName == "votes_user_id_fkey" {
+		return apperrors.ErrUserNotFound
+	}
+	return apperrors.ErrVoteVariantNotFound
+}
+
+// Какой из родителей голоса отсутствует или мягко удален
+func missingVoteReference(ctx context.Context, tx pgx.Tx, userID string) error {
+	pp := "internal/database/postgres/repository/missingVoteReference"
+
+	const query = `
+	SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND deleted_at IS NULL)`
+
+	var userExists bool
+	if err := tx.QueryRow(ctx, query, userID).Scan(&userExists); err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+	if !userExists {
+		return apperrors.ErrUserNotFound
+	}
+
+	return apperrors.ErrVoteVariantNotFound
+}
+
+// Пишется в той же транзакции, что и сам голос
+func insertVoteChange(ctx context.Context, tx pgx.Tx, voteID, userID string, oldVariantID *string, newVariantID string, changedAt time.Time) error {
+	const query = `
+	INSERT INTO vote_history (vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+	VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.Exec(ctx, query, voteID, userID, oldVariantID, newVariantID, changedAt)
+	return err
+}
@@ -6,30 +6,33 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
 	"github.com/alonsoF100/golos/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (r Repository) CreateVoteVariant(id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error) {
+func (r Repository) CreateVoteVariant(ctx context.Context, id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error) {
 	pp := "internal/database/postgres/repository/CreateVoteVariant"
 
 	const query = `
 	INSERT INTO vote_variants (id, election_id, name, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5)
-	RETURNING id, election_id, name, created_at, updated_at`
+	SELECT $1, e.id, $3, $4, $5 FROM elections e
+	WHERE e.id = $2 AND e.deleted_at IS NULL
+	RETURNING id, election_id, name, created_at, updated_at, version`
 
 	var voteVariant models.VoteVariant
-	err := r.pool.QueryRow(context.Background(), query, id, electionID, name, createdAt, updatedAt).Scan(
+	err := r.pool.QueryRow(ctx, query, id, electionID, name, createdAt, updatedAt).Scan(
 		&voteVariant.ID,
 		&voteVariant.ElectionID,
 		&voteVariant.Name,
 		&voteVariant.CreatedAt,
-		&voteVariant.UpdatedAt)
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		if errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "23503" {
 			return nil, apperrors.ErrElectionNotFound
 		}
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
@@ -38,7 +41,7 @@ func (r Repository) CreateVoteVariant(id, electionID, name string, createdAt tim
 	return &voteVariant, nil
 }
 
-func (r Repository) GetVoteVariants(electionID string) ([]*models.VoteVariant, error) {
+func (r Repository) GetVoteVariants(ctx context.Context, electionID string) ([]*models.VoteVariant, error) {
 	pp := "internal/database/postgres/repository/GetVoteVariants"
 
 	if electionID == "" {
@@ -46,51 +49,81 @@ func (r Repository) GetVoteVariants(electionID string) ([]*models.VoteVariant, e
 	}
 
 	const query = `
-	SELECT id, election_id, name, created_at, updated_at FROM vote_variants
-	WHERE election_id = $1`
+	SELECT vv.id, vv.election_id, vv.name, vv.created_at, vv.updated_at, vv.version
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = $1 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
 
-	rows, err := r.pool.Query(context.Background(), query, electionID)
+	voteVariants, err := readRows(ctx, r, func(row pgx.CollectableRow) (*models.VoteVariant, error) {
+		var voteVariant models.VoteVariant
+		err := row.Scan(
+			&voteVariant.ID,
+			&voteVariant.ElectionID,
+			&voteVariant.Name,
+			&voteVariant.CreatedAt,
+			&voteVariant.UpdatedAt,
+			&voteVariant.Version)
+		return &voteVariant, err
+	}, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return voteVariants, nil
+}
+
+// ID вариантов читаются из primary: по ним ищутся голоса пользователя,
+// и только что созданный вариант не должен теряться из-за отставания реплики
+func (r Repository) GetVoteVariantIDs(ctx context.Context, electionID string) ([]string, error) {
+	pp := "internal/database/postgres/repository/GetVoteVariantIDs"
+
+	if electionID == "" {
+		return nil, nil
+	}
+
+	const query = `
+	SELECT vv.id FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = $1 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
+
+	rows, err := r.pool.Query(ctx, query, electionID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 	defer rows.Close()
 
-	var voteVariants []*models.VoteVariant
+	var ids []string
 	for rows.Next() {
-		var voteVariant models.VoteVariant
-		err := rows.Scan(
-			&voteVariant.ID,
-			&voteVariant.ElectionID,
-			&voteVariant.Name,
-			&voteVariant.CreatedAt,
-			&voteVariant.UpdatedAt)
-		if err != nil {
+		var id string
+		if err := rows.Scan(&id); err != nil {
 			return nil, fmt.Errorf("%s: error: %w", pp, err)
 		}
-
-		voteVariants = append(voteVariants, &voteVariant)
+		ids = append(ids, id)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: error: %w", pp, err)
 	}
 
-	return voteVariants, nil
+	return ids, nil
 }
 
-func (r Repository) GetVoteVariant(id string) (*models.VoteVariant, error) {
+func (r Repository) GetVoteVariant(ctx context.Context, id string) (*models.VoteVariant, error) {
 	pp := "internal/database/postgres/repository/GetVoteVariant"
 
 	const query = `
-	SELECT id, election_id, name, created_at, updated_at FROM vote_variants
-	WHERE id = $1`
+	SELECT vv.id, vv.election_id, vv.name, vv.created_at, vv.updated_at, vv.version
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.id = $1 AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
 
 	var voteVariant models.VoteVariant
-	err := r.pool.QueryRow(context.Background(), query, id).Scan(
+	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&voteVariant.ID,
 		&voteVariant.ElectionID,
 		&voteVariant.Name,
 		&voteVariant.CreatedAt,
-		&voteVariant.UpdatedAt)
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrVoteVariantNotFound
@@ -101,41 +134,81 @@ func (r Repository) GetVoteVariant(id string) (*models.VoteVariant, error) {
 	return &voteVariant, nil
 }
 
-func (r Repository) DeleteVoteVariant(id string) error {
+func (r Repository) DeleteVoteVariant(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
 	pp := "internal/database/postgres/repository/DeleteVoteVariant"
 
-	const query = `
-	DELETE FROM vote_variants
-	WHERE id = $1`
+	query, args, err := squirrel.Update("vote_variants").
+		Set("deleted_at", deletedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		PlaceholderFormat(squirrel.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
 
-	row, err := r.pool.Exec(context.Background(), query, id)
+	row, err := r.pool.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("%s: error: %w", pp, err)
 	}
 
 	if row.RowsAffected() == 0 {
-		return apperrors.ErrElectionNotFound
+		return r.missingOrConflict(ctx, "vote_variants", id, version, apperrors.ErrVoteVariantNotFound)
 	}
 
 	return nil
 }
 
-func (r Repository) UpdateVoteVariant(id, name string, updatedAt time.Time) (*models.VoteVariant, error) {
+func (r Repository) UpdateVoteVariant(ctx context.Context, id, name string, version *int64, updatedAt time.Time) (*models.VoteVariant, error) {
 	pp := "internal/database/postgres/repository/UpdateVoteVariant"
 
+	query, args, err := squirrel.Update("vote_variants").
+		Set("name", name).
+		Set("updated_at", updatedAt).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		PlaceholderFormat(squirrel.Dollar).
+		Suffix("RETURNING id, election_id, name, created_at, updated_at, version").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	var voteVariant models.VoteVariant
+	err = r.pool.QueryRow(ctx, query, args...).Scan(
+		&voteVariant.ID,
+		&voteVariant.ElectionID,
+		&voteVariant.Name,
+		&voteVariant.CreatedAt,
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, "vote_variants", id, version, apperrors.ErrVoteVariantNotFound)
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &voteVariant, nil
+}
+
+func (r Repository) RestoreVoteVariant(ctx context.Context, id string, updatedAt time.Time) (*models.VoteVariant, error) {
+	pp := "internal/database/postgres/repository/RestoreVoteVariant"
+
 	const query = `
 	UPDATE vote_variants
-	SET name = $1, updated_at = $2
-	WHERE id = $3
-	RETURNING id, election_id, name, created_at, updated_at`
+	SET deleted_at = NULL, updated_at = $1, version = version + 1
+	WHERE id = $2 AND deleted_at IS NOT NULL
+	RETURNING id, election_id, name, created_at, updated_at, version`
 
 	var voteVariant models.VoteVariant
-	err := r.pool.QueryRow(context.Background(), query, name, updatedAt, id).Scan(
+	err := r.pool.QueryRow(ctx, query, updatedAt, id).Scan(
 		&voteVariant.ID,
 		&voteVariant.ElectionID,
 		&voteVariant.Name,
 		&voteVariant.CreatedAt,
-		&voteVariant.UpdatedAt)
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperrors.ErrVoteVariantNotFound
@@ -145,3 +218,7 @@ func (r Repository) UpdateVoteVariant(id, name string, updatedAt time.Time) (*mo
 
 	return &voteVariant, nil
 }
+
+func (r Repository) PurgeVoteVariants(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "vote_variants", deletedBefore)
+}
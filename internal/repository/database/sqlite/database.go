@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alonsoF100/golos/internal/config"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/service"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var _ service.Storage = (*Repository)(nil)
+
+// Время хранится строкой фиксированной ширины в UTC: так его можно сравнивать
+// и сортировать как текст, а драйвер читает колонки TIMESTAMP обратно в time.Time
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+type Repository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+// Один файл на узел. Писатель в sqlite всегда один, поэтому пул ограничен
+// одним соединением: запросы ждут в database/sql, а не получают SQLITE_BUSY
+func Open(ctx context.Context, cfg *config.Config) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)", cfg.Database.Path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// NULL для отсутствующего времени
+func nullTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// Расширенный код ошибки ограничения: unique, foreign key и т.д.
+func constraintCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+func isUniqueViolation(err error) bool {
+	code := constraintCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+func isForeignKeyViolation(err error) bool {
+	return constraintCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+// Предикат оптимистичной блокировки, nil - обновление без проверки версии.
+// Мягко удаленные строки изменять нельзя, поэтому они тоже отсекаются здесь
+func withVersion(id string, version *int64) squirrel.Eq {
+	if version == nil {
+		return squirrel.Eq{"id": id, "deleted_at": nil}
+	}
+	return squirrel.Eq{"id": id, "version": *version, "deleted_at": nil}
+}
+
+// Различает отсутствующую строку и устаревшую версию после UPDATE/DELETE без результата
+func (r Repository) missingOrConflict(ctx context.Context, table, id string, version *int64, notFound error) error {
+	if version == nil {
+		return notFound
+	}
+
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = ? AND deleted_at IS NULL)", table)
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return fmt.Errorf("internal/database/sqlite/repository/missingOrConflict: error: %w", err)
+	}
+	if exists {
+		return apperrors.ErrVersionConflict
+	}
+
+	return notFound
+}
+
+// Есть ли неудаленная строка: так sqlite выясняет, какой из родителей отсутствует,
+// имени ограничения в ошибке нет
+func (r Repository) exists(ctx context.Context, table, id string) (bool, error) {
+	var exists bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = ? AND deleted_at IS NULL)", table)
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("internal/database/sqlite/repository/exists: error: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Жесткое удаление строк, мягко удаленных раньше before
+func (r Repository) purgeDeleted(ctx context.Context, table string, before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < ?", table)
+
+	result, err := r.db.ExecContext(ctx, query, timestamp(before))
+	if err != nil {
+		return 0, fmt.Errorf("internal/database/sqlite/repository/purgeDeleted: error: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/repository/database/storagetest"
+	"github.com/alonsoF100/golos/internal/service"
+)
+
+// Каждый сценарий получает свой файл базы во временном каталоге
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) service.Storage {
+		ctx := t.Context()
+
+		cfg := config.Default()
+		cfg.Database.Driver = config.DriverSQLite
+		cfg.Database.Path = filepath.Join(t.TempDir(), "golos.db")
+
+		db, err := Open(ctx, &cfg)
+		if err != nil {
+			t.Fatalf("open sqlite: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		if err := Migrate(ctx, db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+
+		return New(db)
+	})
+}
+
+// Версия перед migrations/sqlite/0012, где появился индекс (user_id, election_id)
+const legacyVotesVersion = 11
+
+func TestDuplicateVotesMigration(t *testing.T) {
+	storagetest.RunDuplicateVotes(t, func(t *testing.T, seed storagetest.LegacyVotes) service.Storage {
+		ctx := t.Context()
+
+		cfg := config.Default()
+		cfg.Database.Driver = config.DriverSQLite
+		cfg.Database.Path = filepath.Join(t.TempDir(), "golos.db")
+
+		db, err := Open(ctx, &cfg)
+		if err != nil {
+			t.Fatalf("open sqlite: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		migrator, err := NewMigrator(db)
+		if err != nil {
+			t.Fatalf("create migrator: %v", err)
+		}
+		if _, err := migrator.UpTo(ctx, legacyVotesVersion); err != nil {
+			t.Fatalf("migrate to %d: %v", legacyVotesVersion, err)
+		}
+
+		exec := func(query string, args ...any) {
+			t.Helper()
+			if _, err := db.ExecContext(ctx, query, args...); err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+		}
+		for _, user := range seed.Users {
+			exec(`INSERT INTO users (id, nickname, password, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+				user.ID, user.Nickname, user.Password, timestamp(user.CreatedAt), timestamp(user.UpdatedAt))
+		}
+		for _, election := range seed.Elections {
+			exec(`INSERT INTO elections (id, user_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				election.ID, election.UserID, election.Name, election.Description, timestamp(election.CreatedAt), timestamp(election.UpdatedAt))
+		}
+		for _, voteVariant := range seed.VoteVariants {
+			exec(`INSERT INTO vote_variants (id, election_id, name, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+				voteVariant.ID, voteVariant.ElectionID, voteVariant.Name, timestamp(voteVariant.CreatedAt), timestamp(voteVariant.UpdatedAt))
+		}
+		for _, vote := range seed.Votes {
+			exec(`INSERT INTO votes (id, user_id, variant_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+				vote.ID, vote.UserID, vote.VariantID, timestamp(vote.CreatedAt), timestamp(vote.UpdatedAt))
+		}
+
+		if err := Migrate(ctx, db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+
+		return New(db)
+	})
+}
@@ -0,0 +1,284 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) CreateElection(ctx context.Context, id, userID, name string, description string, votePolicy string, createdAt time.Time, updatedAt time.Time) (*models.Election, error) {
+	pp := "internal/database/sqlite/repository/CreateElection"
+
+	// Мягко удаленный пользователь проходит проверку внешнего ключа, поэтому строка вставляется только для живого
+	const query = `
+	INSERT INTO elections (id, user_id, name, description, vote_policy, created_at, updated_at)
+	SELECT ?, u.id, ?, ?, ?, ?, ? FROM users u
+	WHERE u.id = ? AND u.deleted_at IS NULL
+	RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version`
+
+	var election models.Election
+	err := r.db.QueryRowContext(ctx, query, id, name, description, votePolicy, timestamp(createdAt), timestamp(updatedAt), userID).Scan(
+		&election.ID,
+		&election.UserID,
+		&election.Name,
+		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
+		&election.CreatedAt,
+		&election.UpdatedAt,
+		&election.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || isForeignKeyViolation(err) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &election, nil
+}
+
+func (r Repository) GetElections(ctx context.Context, limit, offset int, filter models.ElectionFilter) ([]*models.Election, error) {
+	pp := "internal/database/sqlite/repository/GetElections"
+
+	qb := squirrel.
+		Select("id", "user_id", "name", "description", "status", "vote_policy", "created_at", "updated_at", "version").
+		From("elections").
+		Where(squirrel.Eq{"deleted_at": nil})
+	if filter.UserID != "" {
+		qb = qb.Where(squirrel.Eq{"user_id": filter.UserID})
+	}
+	if filter.Query != "" {
+		qb = qb.Where(searchRankExpr+" > 0", filter.Query)
+	}
+	if filter.Status != "" {
+		qb = qb.Where(squirrel.Eq{"status": filter.Status})
+	}
+	if filter.CreatedAfter != nil {
+		qb = qb.Where(squirrel.GtOrEq{"created_at": timestamp(*filter.CreatedAfter)})
+	}
+	if filter.CreatedBefore != nil {
+		qb = qb.Where(squirrel.LtOrEq{"created_at": timestamp(*filter.CreatedBefore)})
+	}
+	qb = orderElections(qb, filter)
+
+	query, args, err := qb.
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var elections []*models.Election
+	for rows.Next() {
+		var election models.Election
+		err := rows.Scan(
+			&election.ID,
+			&election.UserID,
+			&election.Name,
+			&election.Description,
+			&election.Status,
+			&election.VotePolicy,
+			&election.CreatedAt,
+			&election.UpdatedAt,
+			&election.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		elections = append(elections, &election)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return elections, nil
+}
+
+func (r Repository) GetElection(ctx context.Context, id string) (*models.Election, error) {
+	pp := "internal/database/sqlite/repository/GetElection"
+
+	const query = `
+	SELECT id, user_id, name, description, status, vote_policy, created_at, updated_at, version
+	FROM elections
+	WHERE id = ? AND deleted_at IS NULL`
+
+	var election models.Election
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&election.ID,
+		&election.UserID,
+		&election.Name,
+		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
+		&election.CreatedAt,
+		&election.UpdatedAt,
+		&election.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrElectionNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &election, nil
+}
+
+func (r Repository) DeleteElection(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/DeleteElection"
+
+	query, args, err := squirrel.Update("elections").
+		Set("deleted_at", timestamp(deletedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	row, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := row.RowsAffected(); affected == 0 {
+		return r.missingOrConflict(ctx, "elections", id, version, apperrors.ErrElectionNotFound)
+	}
+
+	return nil
+}
+
+func (r Repository) PatchElection(ctx context.Context, id string, userID, name, description, status, votePolicy *string, version *int64, updatedAt time.Time) (*models.Election, error) {
+	pp := "internal/database/sqlite/repository/PatchElection"
+
+	qb := squirrel.Update("elections").
+		Set("updated_at", timestamp(updatedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version))
+	// Новый владелец должен существовать и не быть мягко удаленным
+	if userID != nil {
+		qb = qb.Set("user_id", *userID).
+			Where("EXISTS (SELECT 1 FROM users WHERE id = ? AND deleted_at IS NULL)", *userID)
+	}
+	if name != nil {
+		qb = qb.Set("name", *name)
+	}
+	if description != nil {
+		qb = qb.Set("description", *description)
+	}
+	if status != nil {
+		qb = qb.Set("status", *status)
+	}
+	if votePolicy != nil {
+		qb = qb.Set("vote_policy", *votePolicy)
+	}
+	query, args, err := qb.
+		Suffix("RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	var election models.Election
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&election.ID,
+		&election.UserID,
+		&election.Name,
+		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
+		&election.CreatedAt,
+		&election.UpdatedAt,
+		&election.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if userID != nil {
+				exists, err := r.exists(ctx, "users", *userID)
+				if err != nil {
+					return nil, fmt.Errorf("%s: error: %w", pp, err)
+				}
+				if !exists {
+					return nil, apperrors.ErrUserNotFound
+				}
+			}
+			return nil, r.missingOrConflict(ctx, "elections", id, version, apperrors.ErrElectionNotFound)
+		}
+		if isForeignKeyViolation(err) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &election, nil
+}
+
+func (r Repository) RestoreElection(ctx context.Context, id string, updatedAt time.Time) (*models.Election, error) {
+	pp := "internal/database/sqlite/repository/RestoreElection"
+
+	const query = `
+	UPDATE elections
+	SET deleted_at = NULL, updated_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NOT NULL
+	RETURNING id, user_id, name, description, status, vote_policy, created_at, updated_at, version`
+
+	var election models.Election
+	err := r.db.QueryRowContext(ctx, query, timestamp(updatedAt), id).Scan(
+		&election.ID,
+		&election.UserID,
+		&election.Name,
+		&election.Description,
+		&election.Status,
+		&election.VotePolicy,
+		&election.CreatedAt,
+		&election.UpdatedAt,
+		&election.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrElectionNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &election, nil
+}
+
+func (r Repository) PurgeElections(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "elections", deletedBefore)
+}
+
+// Сортировка по белому списку полей, по умолчанию сначала новые
+func orderElections(qb squirrel.SelectBuilder, filter models.ElectionFilter) squirrel.SelectBuilder {
+	direction := "DESC"
+	if filter.SortOrder == models.SortOrderAsc {
+		direction = "ASC"
+	}
+
+	switch filter.SortBy {
+	case models.ElectionSortRelevance:
+		if filter.Query == "" {
+			return qb.OrderBy("created_at " + direction)
+		}
+		return qb.OrderByClause(
+			searchRankExpr+" "+direction,
+			filter.Query,
+		).OrderBy("created_at DESC")
+	case models.ElectionSortName:
+		return qb.OrderBy("name "+direction, "created_at DESC")
+	case models.ElectionSortUpdatedAt:
+		return qb.OrderBy("updated_at " + direction)
+	default:
+		return qb.OrderBy("created_at " + direction)
+	}
+}
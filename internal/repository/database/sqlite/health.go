@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r Repository) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("internal/database/sqlite/repository/Ping: error: %w", err)
+	}
+
+	return nil
+}
+
+// Схема в базе должна быть не старее последней миграции (LatestMigration)
+func (r Repository) CheckMigrations(ctx context.Context, latest int64) error {
+	pp := "internal/database/sqlite/repository/CheckMigrations"
+
+	const query = `
+	SELECT COALESCE(MAX(version_id), 0)
+	FROM goose_db_version`
+
+	var current int64
+	if err := r.db.QueryRowContext(ctx, query).Scan(&current); err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+	if current < latest {
+		return fmt.Errorf("%s: schema version %d, latest %d", pp, current, latest)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// Занимает ключ, если его нет или он уже истек, false - ключ занят другим запросом
+func (r Repository) ClaimIdempotencyKey(ctx context.Context, key, requestHash string, createdAt time.Time, expiresAt time.Time) (bool, error) {
+	pp := "internal/database/sqlite/repository/ClaimIdempotencyKey"
+
+	const query = `
+	INSERT INTO idempotency_keys (key, request_hash, status_code, body, created_at, expires_at)
+	VALUES (?, ?, 0, NULL, ?, ?)
+	ON CONFLICT (key) DO UPDATE
+	SET request_hash = EXCLUDED.request_hash,
+		status_code = 0,
+		etag = '',
+		location = '',
+		body = NULL,
+		created_at = EXCLUDED.created_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at < EXCLUDED.created_at`
+
+	row, err := r.db.ExecContext(ctx, query, key, requestHash, timestamp(createdAt), timestamp(expiresAt))
+	if err != nil {
+		return false, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	affected, _ := row.RowsAffected()
+	return affected == 1, nil
+}
+
+func (r Repository) GetIdempotencyRecord(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	pp := "internal/database/sqlite/repository/GetIdempotencyRecord"
+
+	const query = `
+	SELECT key, request_hash, status_code, etag, location, body, created_at, expires_at FROM idempotency_keys
+	WHERE key = ?`
+
+	var record models.IdempotencyRecord
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ETag,
+		&record.Location,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrIdempotencyKeyNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &record, nil
+}
+
+func (r Repository) SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error {
+	pp := "internal/database/sqlite/repository/SaveIdempotencyResponse"
+
+	const query = `
+	UPDATE idempotency_keys
+	SET status_code = ?, etag = ?, location = ?, body = ?
+	WHERE key = ?`
+
+	row, err := r.db.ExecContext(ctx, query, statusCode, etag, location, body, key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := row.RowsAffected(); affected == 0 {
+		return apperrors.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	pp := "internal/database/sqlite/repository/DeleteIdempotencyKey"
+
+	const query = `
+	DELETE FROM idempotency_keys
+	WHERE key = ?`
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	pp := "internal/database/sqlite/repository/DeleteExpiredIdempotencyKeys"
+
+	const query = `
+	DELETE FROM idempotency_keys
+	WHERE expires_at < ?`
+
+	row, err := r.db.ExecContext(ctx, query, timestamp(now))
+	if err != nil {
+		return 0, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return row.RowsAffected()
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) RegisterLoginFailure(ctx context.Context, key string, failedAt time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	pp := "internal/database/sqlite/repository/RegisterLoginFailure"
+
+	// Счетчик сбрасывается, только если и последняя ошибка, и блокировка старше окна,
+	// иначе backoff не рос бы после окончания длинной блокировки
+	const query = `
+	INSERT INTO login_attempts (key, failures, last_failure_at)
+	VALUES (?, 1, ?)
+	ON CONFLICT (key) DO UPDATE
+	SET failures = CASE
+			WHEN login_attempts.last_failure_at < ?
+				AND (login_attempts.locked_until IS NULL OR login_attempts.locked_until < ?)
+			THEN 1
+			ELSE login_attempts.failures + 1
+		END,
+		last_failure_at = EXCLUDED.last_failure_at
+	RETURNING key, failures, last_failure_at, locked_until`
+
+	var attempt models.LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, key, timestamp(failedAt), timestamp(failedAt.Add(-window)), timestamp(failedAt.Add(-window))).Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &attempt, nil
+}
+
+func (r Repository) SetLoginLock(ctx context.Context, key string, lockedUntil time.Time) error {
+	pp := "internal/database/sqlite/repository/SetLoginLock"
+
+	const query = `
+	UPDATE login_attempts
+	SET locked_until = ?
+	WHERE key = ?`
+
+	_, err := r.db.ExecContext(ctx, query, timestamp(lockedUntil), key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	pp := "internal/database/sqlite/repository/GetLoginAttempt"
+
+	const query = `
+	SELECT key, failures, last_failure_at, locked_until FROM login_attempts
+	WHERE key = ?`
+
+	var attempt models.LoginAttempt
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.LoginAttempt{Key: key}, nil
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &attempt, nil
+}
+
+func (r Repository) ResetLoginAttempts(ctx context.Context, key string) error {
+	pp := "internal/database/sqlite/repository/ResetLoginAttempts"
+
+	const query = `
+	DELETE FROM login_attempts
+	WHERE key = ?`
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) CreateLoginLockout(ctx context.Context, id, key string, failures int, lockedUntil time.Time, createdAt time.Time) (*models.LoginLockout, error) {
+	pp := "internal/database/sqlite/repository/CreateLoginLockout"
+
+	const query = `
+	INSERT INTO login_lockouts (id, key, failures, locked_until, created_at)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING id, key, failures, locked_until, created_at`
+
+	var lockout models.LoginLockout
+	err := r.db.QueryRowContext(ctx, query, id, key, failures, timestamp(lockedUntil), timestamp(createdAt)).Scan(
+		&lockout.ID,
+		&lockout.Key,
+		&lockout.Failures,
+		&lockout.LockedUntil,
+		&lockout.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &lockout, nil
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	migrations "github.com/alonsoF100/golos/migrations/sqlite"
+	"github.com/pressly/goose/v3"
+)
+
+// Миграции из migrations/sqlite, глобальный реестр goose принадлежит postgres.
+// Блокировка не нужна: sqlite рассчитан на один узел.
+// Close провайдера закрывает и db, поэтому здесь он не вызывается
+func NewMigrator(db *sql.DB) (*goose.Provider, error) {
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, nil,
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithGoMigrations(migrations.Migrations()...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("internal/database/sqlite/NewMigrator: error: %w", err)
+	}
+
+	return provider, nil
+}
+
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	results, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("internal/database/sqlite/Migrate: error: %w", err)
+	}
+	for _, result := range results {
+		slog.Info("Migration applied", "version", result.Source.Version, "duration", result.Duration)
+	}
+
+	return nil
+}
+
+// Последняя версия среди миграций sqlite, база не запрашивается
+func LatestMigration(db *sql.DB) (int64, error) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+
+	sources := migrator.ListSources()
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	return sources[len(sources)-1].Version, nil
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) GetUserRole(ctx context.Context, userID string) (string, error) {
+	pp := "internal/database/sqlite/repository/GetUserRole"
+
+	const query = `
+	SELECT role FROM user_roles
+	WHERE user_id = ?`
+
+	var role string
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RoleUser, nil
+		}
+		return "", fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return role, nil
+}
+
+func (r Repository) SetUserRole(ctx context.Context, userID, role string, updatedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/SetUserRole"
+
+	const query = `
+	INSERT INTO user_roles (user_id, role, created_at, updated_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (user_id) DO UPDATE
+	SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query, userID, role, timestamp(updatedAt), timestamp(updatedAt))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return apperrors.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+	"strings"
+	"unicode"
+
+	"modernc.org/sqlite"
+)
+
+// Ранг выборов по тексту поиска, аналог ts_rank(search_vector, ...) из postgres
+const searchRankExpr = "golos_search_rank(name || ' ' || COALESCE(description, ''), ?)"
+
+// Замена tsvector из postgres: golos_search_rank(text, query) - число вхождений
+// слов запроса в текст без учета регистра, 0 - найдены не все слова
+func init() {
+	err := sqlite.RegisterDeterministicScalarFunction("golos_search_rank", 2,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			text, _ := args[0].(string)
+			query, _ := args[1].(string)
+			return int64(searchRank(text, query)), nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func searchRank(text, query string) int {
+	words := searchTerms(text)
+
+	total := 0
+	for _, term := range searchTerms(query) {
+		count := 0
+		for _, word := range words {
+			if word == term {
+				count++
+			}
+		}
+		if count == 0 {
+			return 0
+		}
+		total += count
+	}
+
+	return total
+}
+
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
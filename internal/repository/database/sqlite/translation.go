@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) SetElectionTranslation(ctx context.Context, electionID, locale, name, description string, updatedAt time.Time) (*models.ElectionTranslation, error) {
+	pp := "internal/database/sqlite/repository/SetElectionTranslation"
+
+	const query = `
+	INSERT INTO election_translations (election_id, locale, name, description, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (election_id, locale) DO UPDATE
+	SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+	RETURNING election_id, locale, name, description, created_at, updated_at`
+
+	var translation models.ElectionTranslation
+	err := r.db.QueryRowContext(ctx, query, electionID, locale, name, description, timestamp(updatedAt), timestamp(updatedAt)).Scan(
+		&translation.ElectionID,
+		&translation.Locale,
+		&translation.Name,
+		&translation.Description,
+		&translation.CreatedAt,
+		&translation.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperrors.ErrElectionNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &translation, nil
+}
+
+func (r Repository) DeleteElectionTranslation(ctx context.Context, electionID, locale string) error {
+	pp := "internal/database/sqlite/repository/DeleteElectionTranslation"
+
+	const query = `
+	DELETE FROM election_translations
+	WHERE election_id = ? AND locale = ?`
+
+	row, err := r.db.ExecContext(ctx, query, electionID, locale)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := row.RowsAffected(); affected == 0 {
+		return apperrors.ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+// Все переводы выборов electionIDs, locale = "" - на все языки
+func (r Repository) GetElectionTranslations(ctx context.Context, electionIDs []string, locale string) ([]*models.ElectionTranslation, error) {
+	pp := "internal/database/sqlite/repository/GetElectionTranslations"
+
+	qb := squirrel.
+		Select("election_id", "locale", "name", "description", "created_at", "updated_at").
+		From("election_translations").
+		Where(squirrel.Eq{"election_id": electionIDs})
+	if locale != "" {
+		qb = qb.Where(squirrel.Eq{"locale": locale})
+	}
+
+	query, args, err := qb.
+		OrderBy("election_id", "locale").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var translations []*models.ElectionTranslation
+	for rows.Next() {
+		var translation models.ElectionTranslation
+		err := rows.Scan(
+			&translation.ElectionID,
+			&translation.Locale,
+			&translation.Name,
+			&translation.Description,
+			&translation.CreatedAt,
+			&translation.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		translations = append(translations, &translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return translations, nil
+}
+
+func (r Repository) SetVoteVariantTranslation(ctx context.Context, voteVariantID, locale, name string, updatedAt time.Time) (*models.VoteVariantTranslation, error) {
+	pp := "internal/database/sqlite/repository/SetVoteVariantTranslation"
+
+	const query = `
+	INSERT INTO vote_variant_translations (vote_variant_id, locale, name, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (vote_variant_id, locale) DO UPDATE
+	SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+	RETURNING vote_variant_id, locale, name, created_at, updated_at`
+
+	var translation models.VoteVariantTranslation
+	err := r.db.QueryRowContext(ctx, query, voteVariantID, locale, name, timestamp(updatedAt), timestamp(updatedAt)).Scan(
+		&translation.VoteVariantID,
+		&translation.Locale,
+		&translation.Name,
+		&translation.CreatedAt,
+		&translation.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperrors.ErrVoteVariantNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &translation, nil
+}
+
+func (r Repository) DeleteVoteVariantTranslation(ctx context.Context, voteVariantID, locale string) error {
+	pp := "internal/database/sqlite/repository/DeleteVoteVariantTranslation"
+
+	const query = `
+	DELETE FROM vote_variant_translations
+	WHERE vote_variant_id = ? AND locale = ?`
+
+	row, err := r.db.ExecContext(ctx, query, voteVariantID, locale)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := row.RowsAffected(); affected == 0 {
+		return apperrors.ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+// Все переводы вариантов voteVariantIDs, locale = "" - на все языки
+func (r Repository) GetVoteVariantTranslations(ctx context.Context, voteVariantIDs []string, locale string) ([]*models.VoteVariantTranslation, error) {
+	pp := "internal/database/sqlite/repository/GetVoteVariantTranslations"
+
+	qb := squirrel.
+		Select("vote_variant_id", "locale", "name", "created_at", "updated_at").
+		From("vote_variant_translations").
+		Where(squirrel.Eq{"vote_variant_id": voteVariantIDs})
+	if locale != "" {
+		qb = qb.Where(squirrel.Eq{"locale": locale})
+	}
+
+	query, args, err := qb.
+		OrderBy("vote_variant_id", "locale").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var translations []*models.VoteVariantTranslation
+	for rows.Next() {
+		var translation models.VoteVariantTranslation
+		err := rows.Scan(
+			&translation.VoteVariantID,
+			&translation.Locale,
+			&translation.Name,
+			&translation.CreatedAt,
+			&translation.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		translations = append(translations, &translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return translations, nil
+}
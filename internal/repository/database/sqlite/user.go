@@ -0,0 +1,255 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) CreateUser(ctx context.Context, id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error) {
+	pp := "internal/database/sqlite/repository/CreateUser"
+
+	const query = `
+	INSERT INTO users (id, nickname, password, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING id, nickname, password, created_at, updated_at, version`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, id, nickname, password, timestamp(createdAt), timestamp(updatedAt)).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrUserAlreadyExist
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) GetUsers(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	pp := "internal/database/sqlite/repository/GetUsers"
+
+	query, args, err := squirrel.
+		Select("id", "nickname", "password", "created_at", "updated_at", "version").
+		From("users").
+		Where(squirrel.Eq{"deleted_at": nil}).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+
+		err := rows.Scan(
+			&user.ID,
+			&user.Nickname,
+			&user.Password,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return users, nil
+}
+
+func (r Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	pp := "internal/database/sqlite/repository/GetUser"
+
+	const query = `
+	SELECT id, nickname, password, created_at, updated_at, version FROM users
+	WHERE id = ? AND deleted_at IS NULL`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) UpdateUser(ctx context.Context, id, nickname string, version *int64, updatedAt time.Time) (*models.User, error) {
+	return r.PatchUser(ctx, id, &nickname, version, updatedAt)
+}
+
+func (r Repository) UpdateUserPassword(ctx context.Context, id, password string, updatedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/UpdateUserPassword"
+
+	const query = `
+	UPDATE users
+	SET password = ?, updated_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, password, timestamp(updatedAt), id)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return apperrors.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r Repository) DeleteUser(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/DeleteUser"
+
+	query, args, err := squirrel.Update("users").
+		Set("deleted_at", timestamp(deletedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return r.missingOrConflict(ctx, "users", id, version, apperrors.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+func (r Repository) PatchUser(ctx context.Context, id string, nickname *string, version *int64, updatedAt time.Time) (*models.User, error) {
+	pp := "internal/database/sqlite/repository/PatchUser"
+
+	qb := squirrel.Update("users").
+		Set("updated_at", timestamp(updatedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version))
+	if nickname != nil {
+		qb = qb.Set("nickname", *nickname)
+	}
+	query, args, err := qb.
+		Suffix("RETURNING id, nickname, password, created_at, updated_at, version").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	var user models.User
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, "users", id, version, apperrors.ErrUserNotFound)
+		}
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrUserAlreadyExist
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) GetUserByNickname(ctx context.Context, nickname string) (*models.User, error) {
+	pp := "internal/database/sqlite/repository/GetUserByNickname"
+
+	const query = `
+	SELECT id, nickname, password, created_at, updated_at, version FROM users
+	WHERE nickname = ? AND deleted_at IS NULL`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, nickname).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error) {
+	pp := "internal/database/sqlite/repository/RestoreUser"
+
+	const query = `
+	UPDATE users
+	SET deleted_at = NULL, updated_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NOT NULL
+	RETURNING id, nickname, password, created_at, updated_at, version`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, timestamp(updatedAt), id).Scan(
+		&user.ID,
+		&user.Nickname,
+		&user.Password,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrUserAlreadyExist
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &user, nil
+}
+
+func (r Repository) PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "users", deletedBefore)
+}
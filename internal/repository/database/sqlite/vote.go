@@ -0,0 +1,399 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/google/uuid"
+)
+
+func (r Repository) CreateVote(ctx context.Context, id, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error) {
+	pp := "internal/database/sqlite/repository/CreateVote"
+
+	// Голос за мягко удаленный вариант или от мягко удаленного пользователя не вставляется.
+	// election_id берется из варианта: уникальный индекс (user_id, election_id) - один голос на выборы
+	const query = `
+	INSERT INTO votes (id, user_id, variant_id, election_id, created_at, updated_at)
+	SELECT ?, u.id, vv.id, vv.election_id, ?, ?
+	FROM users u, vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE u.id = ? AND u.deleted_at IS NULL
+		AND vv.id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
+	RETURNING id, user_id, variant_id, created_at, updated_at`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer tx.Rollback()
+
+	var vote models.Vote
+	err = tx.QueryRowContext(ctx, query, id, timestamp(createdAt), timestamp(updatedAt), userID, voteVariantID).Scan(
+		&vote.ID,
+		&vote.UserID,
+		&vote.VariantID,
+		&vote.CreatedAt,
+		&vote.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || isForeignKeyViolation(err) {
+			tx.Rollback()
+			return nil, r.voteReferenceError(ctx, &userID)
+		}
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrVoteAlreadyExist
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if err := insertVoteChange(ctx, tx, vote.ID, vote.UserID, nil, vote.VariantID, createdAt); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &vote, nil
+}
+
+func (r Repository) GetVote(ctx context.Context, uuid string) (*models.Vote, error) {
+	pp := "internal/database/sqlite/repository/GetVote"
+
+	const query = `
+	SELECT id, user_id, variant_id, created_at, updated_at FROM votes
+	WHERE id = ?`
+
+	var vote models.Vote
+	err := r.db.QueryRowContext(ctx, query, uuid).Scan(
+		&vote.ID,
+		&vote.UserID,
+		&vote.VariantID,
+		&vote.CreatedAt,
+		&vote.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrVoteNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &vote, nil
+}
+
+// Отзыв голоса остается в истории записью без new_variant_id
+func (r Repository) DeleteVote(ctx context.Context, id string, deletedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/DeleteVote"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer tx.Rollback()
+
+	const query = `
+	DELETE FROM votes
+	WHERE id = ?
+	RETURNING user_id, variant_id`
+
+	var userID, variantID string
+	err = tx.QueryRowContext(ctx, query, id).Scan(&userID, &variantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.ErrVoteNotFound
+		}
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	const historyQuery = `
+	INSERT INTO vote_history (id, vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+	VALUES (?, ?, ?, ?, NULL, ?)`
+
+	_, err = tx.ExecContext(ctx, historyQuery, uuid.NewString(), id, userID, variantID, timestamp(deletedAt))
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return nil
+}
+
+func (r Repository) PatchVote(ctx context.Context, uuid string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error) {
+	pp := "internal/database/sqlite/repository/PatchVote"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer tx.Rollback()
+
+	// FOR UPDATE в sqlite нет: соединение одно, транзакции и так идут по очереди
+	const selectQuery = `
+	SELECT variant_id FROM votes
+	WHERE id = ?`
+
+	var oldVariantID string
+	err = tx.QueryRowContext(ctx, selectQuery, uuid).Scan(&oldVariantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrVoteNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	qb := squirrel.Update("votes").
+		Set("updated_at", timestamp(updatedAt)).
+		Where(squirrel.Eq{"id": uuid})
+	if userID != nil {
+		qb = qb.Set("user_id", *userID)
+	}
+	if voteVariantID != nil {
+		// Неизвестный вариант оставляет прежние выборы, ошибку тогда дает внешний ключ variant_id
+		qb = qb.
+			Set("variant_id", *voteVariantID).
+			Set("election_id", squirrel.Expr("COALESCE((SELECT election_id FROM vote_variants WHERE id = ?), election_id)", *voteVariantID))
+	}
+	query, args, err := qb.
+		Suffix("RETURNING id, user_id, variant_id, created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	var vote models.Vote
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&vote.ID,
+		&vote.UserID,
+		&vote.VariantID,
+		&vote.CreatedAt,
+		&vote.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrVoteNotFound
+		}
+		if isForeignKeyViolation(err) {
+			tx.Rollback()
+			return nil, r.voteReferenceError(ctx, userID)
+		}
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrVoteAlreadyExist
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if vote.VariantID != oldVariantID {
+		if err := insertVoteChange(ctx, tx, vote.ID, vote.UserID, &oldVariantID, vote.VariantID, updatedAt); err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &vote, nil
+}
+
+func (r Repository) GetUserVotes(ctx context.Context, userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error) {
+	pp := "internal/database/sqlite/repository/GetUserVotes"
+
+	qb := squirrel.Select("id", "user_id", "variant_id", "created_at", "updated_at").
+		From("votes").
+		Where(squirrel.Eq{"user_id": userID})
+
+	if len(voteVariantsIDs) > 0 {
+		qb = qb.Where(squirrel.Eq{"variant_id": voteVariantsIDs})
+	}
+
+	query, args, err := qb.OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var votes []*models.Vote
+	for rows.Next() {
+		var vote models.Vote
+
+		err := rows.Scan(
+			&vote.ID,
+			&vote.UserID,
+			&vote.VariantID,
+			&vote.CreatedAt,
+			&vote.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		votes = append(votes, &vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return votes, nil
+}
+
+func (r Repository) GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error) {
+	return nil, nil
+}
+
+// Варианты без голосов тоже попадают в итоги с нулем
+func (r Repository) GetElectionResults(ctx context.Context, electionID string) ([]*models.VariantResult, error) {
+	pp := "internal/database/sqlite/repository/GetElectionResults"
+
+	const query = `
+	SELECT vv.id, vv.name, COUNT(v.id)
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	LEFT JOIN votes v ON v.variant_id = vv.id
+	WHERE vv.election_id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
+	GROUP BY vv.id, vv.name
+	ORDER BY COUNT(v.id) DESC, vv.name`
+
+	rows, err := r.db.QueryContext(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var results []*models.VariantResult
+	for rows.Next() {
+		var result models.VariantResult
+
+		if err := rows.Scan(&result.VariantID, &result.Name, &result.Votes); err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return results, nil
+}
+
+func (r Repository) GetElectionVotes(ctx context.Context, electionID string) ([]*models.Vote, error) {
+	pp := "internal/database/sqlite/repository/GetElectionVotes"
+
+	const query = `
+	SELECT v.id, v.user_id, v.variant_id, v.created_at, v.updated_at
+	FROM votes v
+	JOIN vote_variants vv ON vv.id = v.variant_id
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL
+	ORDER BY v.created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var votes []*models.Vote
+	for rows.Next() {
+		var vote models.Vote
+
+		err := rows.Scan(
+			&vote.ID,
+			&vote.UserID,
+			&vote.VariantID,
+			&vote.CreatedAt,
+			&vote.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		votes = append(votes, &vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return votes, nil
+}
+
+func (r Repository) GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error) {
+	pp := "internal/database/sqlite/repository/GetVoteHistory"
+
+	const query = `
+	SELECT id, vote_id, user_id, COALESCE(old_variant_id, ''), COALESCE(new_variant_id, ''), changed_at
+	FROM vote_history
+	WHERE vote_id = ?
+	ORDER BY changed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, voteID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var changes []*models.VoteChange
+	for rows.Next() {
+		var change models.VoteChange
+		err := rows.Scan(
+			&change.ID,
+			&change.VoteID,
+			&change.UserID,
+			&change.OldVariantID,
+			&change.NewVariantID,
+			&change.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return changes, nil
+}
+
+// Имени внешнего ключа в ошибке sqlite нет, поэтому сначала проверяется пользователь.
+// Вызывается после отката транзакции: соединение в пуле одно
+func (r Repository) voteReferenceError(ctx context.Context, userID *string) error {
+	if userID != nil {
+		exists, err := r.exists(ctx, "users", *userID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperrors.ErrUserNotFound
+		}
+	}
+	return apperrors.ErrVoteVariantNotFound
+}
+
+// Пишется в той же транзакции, что и сам голос
+func insertVoteChange(ctx context.Context, tx *sql.Tx, voteID, userID string, oldVariantID *string, newVariantID string, changedAt time.Time) error {
+	const query = `
+	INSERT INTO vote_history (id, vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.ExecContext(ctx, query, uuid.NewString(), voteID, userID, oldVariantID, newVariantID, timestamp(changedAt))
+	return err
+}
@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (r Repository) CreateVoteVariant(ctx context.Context, id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error) {
+	pp := "internal/database/sqlite/repository/CreateVoteVariant"
+
+	const query = `
+	INSERT INTO vote_variants (id, election_id, name, created_at, updated_at)
+	SELECT ?, e.id, ?, ?, ? FROM elections e
+	WHERE e.id = ? AND e.deleted_at IS NULL
+	RETURNING id, election_id, name, created_at, updated_at, version`
+
+	var voteVariant models.VoteVariant
+	err := r.db.QueryRowContext(ctx, query, id, name, timestamp(createdAt), timestamp(updatedAt), electionID).Scan(
+		&voteVariant.ID,
+		&voteVariant.ElectionID,
+		&voteVariant.Name,
+		&voteVariant.CreatedAt,
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || isForeignKeyViolation(err) {
+			return nil, apperrors.ErrElectionNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &voteVariant, nil
+}
+
+func (r Repository) GetVoteVariants(ctx context.Context, electionID string) ([]*models.VoteVariant, error) {
+	pp := "internal/database/sqlite/repository/GetVoteVariants"
+
+	if electionID == "" {
+		return nil, nil
+	}
+
+	const query = `
+	SELECT vv.id, vv.election_id, vv.name, vv.created_at, vv.updated_at, vv.version
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var voteVariants []*models.VoteVariant
+	for rows.Next() {
+		var voteVariant models.VoteVariant
+		err := rows.Scan(
+			&voteVariant.ID,
+			&voteVariant.ElectionID,
+			&voteVariant.Name,
+			&voteVariant.CreatedAt,
+			&voteVariant.UpdatedAt,
+			&voteVariant.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+
+		voteVariants = append(voteVariants, &voteVariant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return voteVariants, nil
+}
+
+func (r Repository) GetVoteVariantIDs(ctx context.Context, electionID string) ([]string, error) {
+	pp := "internal/database/sqlite/repository/GetVoteVariantIDs"
+
+	if electionID == "" {
+		return nil, nil
+	}
+
+	const query = `
+	SELECT vv.id FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.election_id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, electionID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: error: %w", pp, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return ids, nil
+}
+
+func (r Repository) GetVoteVariant(ctx context.Context, id string) (*models.VoteVariant, error) {
+	pp := "internal/database/sqlite/repository/GetVoteVariant"
+
+	const query = `
+	SELECT vv.id, vv.election_id, vv.name, vv.created_at, vv.updated_at, vv.version
+	FROM vote_variants vv
+	JOIN elections e ON e.id = vv.election_id
+	WHERE vv.id = ? AND vv.deleted_at IS NULL AND e.deleted_at IS NULL`
+
+	var voteVariant models.VoteVariant
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&voteVariant.ID,
+		&voteVariant.ElectionID,
+		&voteVariant.Name,
+		&voteVariant.CreatedAt,
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrVoteVariantNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &voteVariant, nil
+}
+
+func (r Repository) DeleteVoteVariant(ctx context.Context, id string, version *int64, deletedAt time.Time) error {
+	pp := "internal/database/sqlite/repository/DeleteVoteVariant"
+
+	query, args, err := squirrel.Update("vote_variants").
+		Set("deleted_at", timestamp(deletedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	row, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	if affected, _ := row.RowsAffected(); affected == 0 {
+		return r.missingOrConflict(ctx, "vote_variants", id, version, apperrors.ErrVoteVariantNotFound)
+	}
+
+	return nil
+}
+
+func (r Repository) UpdateVoteVariant(ctx context.Context, id, name string, version *int64, updatedAt time.Time) (*models.VoteVariant, error) {
+	pp := "internal/database/sqlite/repository/UpdateVoteVariant"
+
+	query, args, err := squirrel.Update("vote_variants").
+		Set("name", name).
+		Set("updated_at", timestamp(updatedAt)).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(withVersion(id, version)).
+		Suffix("RETURNING id, election_id, name, created_at, updated_at, version").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	var voteVariant models.VoteVariant
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+		&voteVariant.ID,
+		&voteVariant.ElectionID,
+		&voteVariant.Name,
+		&voteVariant.CreatedAt,
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.missingOrConflict(ctx, "vote_variants", id, version, apperrors.ErrVoteVariantNotFound)
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &voteVariant, nil
+}
+
+func (r Repository) RestoreVoteVariant(ctx context.Context, id string, updatedAt time.Time) (*models.VoteVariant, error) {
+	pp := "internal/database/sqlite/repository/RestoreVoteVariant"
+
+	const query = `
+	UPDATE vote_variants
+	SET deleted_at = NULL, updated_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NOT NULL
+	RETURNING id, election_id, name, created_at, updated_at, version`
+
+	var voteVariant models.VoteVariant
+	err := r.db.QueryRowContext(ctx, query, timestamp(updatedAt), id).Scan(
+		&voteVariant.ID,
+		&voteVariant.ElectionID,
+		&voteVariant.Name,
+		&voteVariant.CreatedAt,
+		&voteVariant.UpdatedAt,
+		&voteVariant.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrVoteVariantNotFound
+		}
+		return nil, fmt.Errorf("%s: error: %w", pp, err)
+	}
+
+	return &voteVariant, nil
+}
+
+func (r Repository) PurgeVoteVariants(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return r.purgeDeleted(ctx, "vote_variants", deletedBefore)
+}
@@ -0,0 +1,119 @@
+package storagetest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var auditCases = []testCase{
+	{"create and filter", testAuditCreateAndFilter},
+}
+
+func testAuditCreateAndFilter(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	actorID := uuid.NewString()
+	userID := uuid.NewString()
+	electionID := uuid.NewString()
+
+	events := []*models.AuditEvent{
+		{
+			ID:         uuid.NewString(),
+			ActorID:    "",
+			Action:     models.AuditActionCreate,
+			EntityType: models.AuditEntityUser,
+			EntityID:   userID,
+			After:      []byte(`{"nickname":"alice"}`),
+			CreatedAt:  at(0),
+		},
+		{
+			ID:         uuid.NewString(),
+			ActorID:    actorID,
+			Action:     models.AuditActionPatch,
+			EntityType: models.AuditEntityUser,
+			EntityID:   userID,
+			Before:     []byte(`{"nickname":"alice"}`),
+			After:      []byte(`{"nickname":"alice2"}`),
+			RequestID:  "req-2",
+			CreatedAt:  at(1),
+		},
+		{
+			ID:         uuid.NewString(),
+			ActorID:    actorID,
+			Action:     models.AuditActionCreate,
+			EntityType: models.AuditEntityElection,
+			EntityID:   electionID,
+			After:      []byte(`{"name":"Lunch"}`),
+			RequestID:  "req-3",
+			CreatedAt:  at(2),
+		},
+	}
+	for _, event := range events {
+		requireNoError(t, s.CreateAuditEvent(ctx, event))
+	}
+
+	eventID := func(e *models.AuditEvent) string { return e.ID }
+
+	got, err := s.GetAuditEvents(ctx, 10, 0, models.AuditFilter{})
+	requireNoError(t, err)
+	requireIDs(t, "all", ids(got, eventID), events[2].ID, events[1].ID, events[0].ID)
+
+	// Пустые actor_id и request_id возвращаются пустыми строками, снимки - как JSON
+	anonymous := got[2]
+	requireEqual(t, "actor_id", anonymous.ActorID, "")
+	requireEqual(t, "request_id", anonymous.RequestID, "")
+	requireEqual(t, "action", anonymous.Action, models.AuditActionCreate)
+	requireEqual(t, "entity_type", anonymous.EntityType, models.AuditEntityUser)
+	requireEqual(t, "entity_id", anonymous.EntityID, userID)
+	requireLen(t, "before", anonymous.Before, 0)
+	requireJSON(t, "after", anonymous.After, events[0].After)
+	requireTime(t, "created_at", anonymous.CreatedAt, at(0))
+
+	patch := got[1]
+	requireEqual(t, "actor_id", patch.ActorID, actorID)
+	requireEqual(t, "request_id", patch.RequestID, "req-2")
+	requireJSON(t, "before", patch.Before, events[1].Before)
+	requireJSON(t, "after", patch.After, events[1].After)
+
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		filter models.AuditFilter
+		want   []string
+	}{
+		{"entity type", 10, 0, models.AuditFilter{EntityType: models.AuditEntityUser}, []string{events[1].ID, events[0].ID}},
+		{"entity", 10, 0, models.AuditFilter{EntityType: models.AuditEntityElection, EntityID: electionID}, []string{events[2].ID}},
+		{"actor", 10, 0, models.AuditFilter{ActorID: actorID}, []string{events[2].ID, events[1].ID}},
+		{"from", 10, 0, models.AuditFilter{From: ptr(at(1))}, []string{events[2].ID, events[1].ID}},
+		{"to", 10, 0, models.AuditFilter{To: ptr(at(1))}, []string{events[1].ID, events[0].ID}},
+		{"first page", 2, 0, models.AuditFilter{}, []string{events[2].ID, events[1].ID}},
+		{"last page", 2, 2, models.AuditFilter{}, []string{events[0].ID}},
+		{"offset past the end", 2, 3, models.AuditFilter{}, nil},
+		{"zero limit", 0, 0, models.AuditFilter{}, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.GetAuditEvents(ctx, tt.limit, tt.offset, tt.filter)
+		requireNoError(t, err)
+		requireIDs(t, tt.name, ids(got, eventID), tt.want...)
+	}
+}
+
+// postgres хранит снимки в JSONB и меняет форматирование, поэтому сравнивается содержимое
+func requireJSON(t *testing.T, field string, got, want []byte) {
+	t.Helper()
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("%s: invalid JSON %q: %v", field, got, err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("%s: invalid JSON %q: %v", field, want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("%s: expected %s, got %s", field, want, got)
+	}
+}
@@ -0,0 +1,121 @@
+package storagetest
+
+import (
+	"testing"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var authCases = []testCase{
+	{"roles", testRoles},
+	{"login attempts", testLoginAttempts},
+	{"login lockouts", testLoginLockouts},
+}
+
+func testRoles(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+
+	// Без записи пользователь обычный
+	role, err := s.GetUserRole(ctx, user.ID)
+	requireNoError(t, err)
+	requireEqual(t, "default role", role, models.RoleUser)
+
+	requireNoError(t, s.SetUserRole(ctx, user.ID, models.RoleModerator, at(1)))
+	role, err = s.GetUserRole(ctx, user.ID)
+	requireNoError(t, err)
+	requireEqual(t, "role", role, models.RoleModerator)
+
+	requireNoError(t, s.SetUserRole(ctx, user.ID, models.RoleAdmin, at(2)))
+	role, err = s.GetUserRole(ctx, user.ID)
+	requireNoError(t, err)
+	requireEqual(t, "updated role", role, models.RoleAdmin)
+
+	requireError(t, s.SetUserRole(ctx, uuid.NewString(), models.RoleAdmin, at(3)), apperrors.ErrUserNotFound)
+}
+
+func testLoginAttempts(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	const key = "nickname:alice"
+	window := 10 * time.Minute
+
+	attempt, err := s.GetLoginAttempt(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "key", attempt.Key, key)
+	requireEqual(t, "failures", attempt.Failures, 0)
+
+	// Блокировка без счетчика ничего не делает
+	requireNoError(t, s.SetLoginLock(ctx, key, at(100)))
+	attempt, err = s.GetLoginAttempt(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "failures", attempt.Failures, 0)
+
+	attempt, err = s.RegisterLoginFailure(ctx, key, at(0), window)
+	requireNoError(t, err)
+	requireEqual(t, "key", attempt.Key, key)
+	requireEqual(t, "failures", attempt.Failures, 1)
+	requireTime(t, "last_failure_at", attempt.LastFailureAt, at(0))
+	if attempt.LockedUntil != nil {
+		t.Fatalf("locked_until: expected nil, got %v", *attempt.LockedUntil)
+	}
+
+	attempt, err = s.RegisterLoginFailure(ctx, key, at(5), window)
+	requireNoError(t, err)
+	requireEqual(t, "failures within window", attempt.Failures, 2)
+
+	requireNoError(t, s.SetLoginLock(ctx, key, at(30)))
+	attempt, err = s.GetLoginAttempt(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "failures", attempt.Failures, 2)
+	requireTime(t, "last_failure_at", attempt.LastFailureAt, at(5))
+	if attempt.LockedUntil == nil {
+		t.Fatal("locked_until: expected lock")
+	}
+	requireTime(t, "locked_until", *attempt.LockedUntil, at(30))
+
+	// Последняя ошибка старше окна, но блокировка еще свежая - счетчик растет
+	attempt, err = s.RegisterLoginFailure(ctx, key, at(35), window)
+	requireNoError(t, err)
+	requireEqual(t, "failures after recent lock", attempt.Failures, 3)
+	requireTime(t, "last_failure_at", attempt.LastFailureAt, at(35))
+
+	// И ошибка, и блокировка старше окна - счетчик сбрасывается
+	attempt, err = s.RegisterLoginFailure(ctx, key, at(60), window)
+	requireNoError(t, err)
+	requireEqual(t, "failures after window", attempt.Failures, 1)
+
+	other, err := s.RegisterLoginFailure(ctx, "ip:127.0.0.1", at(60), window)
+	requireNoError(t, err)
+	requireEqual(t, "other key failures", other.Failures, 1)
+
+	requireNoError(t, s.ResetLoginAttempts(ctx, key))
+	attempt, err = s.GetLoginAttempt(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "failures after reset", attempt.Failures, 0)
+	requireNoError(t, s.ResetLoginAttempts(ctx, key))
+
+	other, err = s.GetLoginAttempt(ctx, "ip:127.0.0.1")
+	requireNoError(t, err)
+	requireEqual(t, "other key after reset", other.Failures, 1)
+}
+
+func testLoginLockouts(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	id := uuid.NewString()
+
+	lockout, err := s.CreateLoginLockout(ctx, id, "nickname:alice", 5, at(15), at(0))
+	requireNoError(t, err)
+	requireEqual(t, "id", lockout.ID, id)
+	requireEqual(t, "key", lockout.Key, "nickname:alice")
+	requireEqual(t, "failures", lockout.Failures, 5)
+	requireTime(t, "locked_until", lockout.LockedUntil, at(15))
+	requireTime(t, "created_at", lockout.CreatedAt, at(0))
+
+	// Журнал блокировок, один ключ может блокироваться много раз
+	_, err = s.CreateLoginLockout(ctx, uuid.NewString(), "nickname:alice", 6, at(45), at(30))
+	requireNoError(t, err)
+}
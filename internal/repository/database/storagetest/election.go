@@ -0,0 +1,221 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var electionCases = []testCase{
+	{"create and get", testElectionCreateAndGet},
+	{"filters", testElectionFilters},
+	{"sorting and pagination", testElectionSortingAndPagination},
+	{"patch", testElectionPatch},
+	{"delete, restore and purge", testElectionDeleteRestorePurge},
+}
+
+func electionID(e *models.Election) string { return e.ID }
+
+func testElectionCreateAndGet(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	id := uuid.NewString()
+
+	election, err := s.CreateElection(ctx, id, user.ID, "Lunch", "Where to eat", models.VotePolicyFinal, at(1), at(2))
+	requireNoError(t, err)
+	requireEqual(t, "id", election.ID, id)
+	requireEqual(t, "user_id", election.UserID, user.ID)
+	requireEqual(t, "name", election.Name, "Lunch")
+	requireEqual(t, "description", election.Description, "Where to eat")
+	requireEqual(t, "status", election.Status, models.ElectionStatusOpen)
+	requireEqual(t, "vote_policy", election.VotePolicy, models.VotePolicyFinal)
+	requireTime(t, "created_at", election.CreatedAt, at(1))
+	requireTime(t, "updated_at", election.UpdatedAt, at(2))
+	requireEqual(t, "version", election.Version, 1)
+
+	got, err := s.GetElection(ctx, id)
+	requireNoError(t, err)
+	requireEqual(t, "name", got.Name, "Lunch")
+	requireEqual(t, "vote_policy", got.VotePolicy, models.VotePolicyFinal)
+
+	_, err = s.GetElection(ctx, uuid.NewString())
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	_, err = s.CreateElection(ctx, uuid.NewString(), uuid.NewString(), "Dinner", "Where to eat", models.VotePolicyFinal, at(3), at(3))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	// Мягко удаленный пользователь проходит внешний ключ, но выборы от него не создаются
+	deleted := newUser(t, s, "bob", at(3))
+	requireNoError(t, s.DeleteUser(ctx, deleted.ID, nil, at(4)))
+	_, err = s.CreateElection(ctx, uuid.NewString(), deleted.ID, "Dinner", "Where to eat", models.VotePolicyFinal, at(5), at(5))
+	requireError(t, err, apperrors.ErrUserNotFound)
+}
+
+func testElectionFilters(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(0))
+
+	lunch := newElection(t, s, alice.ID, "Lunch", "Pizza or sushi", at(1))
+	dinner := newElection(t, s, alice.ID, "Dinner", "Pasta or pizza", at(2))
+	trip := newElection(t, s, bob.ID, "Trip", "Mountains or sea", at(3))
+	deleted := newElection(t, s, bob.ID, "Party", "Pizza party", at(4))
+	requireNoError(t, s.DeleteElection(ctx, deleted.ID, nil, at(5)))
+	_, err := s.PatchElection(ctx, trip.ID, nil, nil, nil, ptr(models.ElectionStatusClosed), nil, nil, at(6))
+	requireNoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter models.ElectionFilter
+		want   []string
+	}{
+		{"no filter", models.ElectionFilter{}, []string{trip.ID, dinner.ID, lunch.ID}},
+		{"user", models.ElectionFilter{UserID: alice.ID}, []string{dinner.ID, lunch.ID}},
+		{"status", models.ElectionFilter{Status: models.ElectionStatusClosed}, []string{trip.ID}},
+		{"query", models.ElectionFilter{Query: "pizza"}, []string{dinner.ID, lunch.ID}},
+		{"query all words", models.ElectionFilter{Query: "PIZZA sushi"}, []string{lunch.ID}},
+		{"query no match", models.ElectionFilter{Query: "burger"}, nil},
+		{"created after", models.ElectionFilter{CreatedAfter: ptr(at(2))}, []string{trip.ID, dinner.ID}},
+		{"created before", models.ElectionFilter{CreatedBefore: ptr(at(2))}, []string{dinner.ID, lunch.ID}},
+		{"combined", models.ElectionFilter{UserID: alice.ID, Query: "pizza", CreatedAfter: ptr(at(2))}, []string{dinner.ID}},
+	}
+	for _, tt := range tests {
+		got, err := s.GetElections(ctx, 10, 0, tt.filter)
+		requireNoError(t, err)
+		requireIDs(t, tt.name, ids(got, electionID), tt.want...)
+	}
+}
+
+func testElectionSortingAndPagination(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+
+	b := newElection(t, s, user.ID, "Bravo", "pizza", at(1))
+	c := newElection(t, s, user.ID, "Charlie", "pizza pizza pizza", at(2))
+	a := newElection(t, s, user.ID, "Alpha", "pizza pizza", at(3))
+	_, err := s.PatchElection(ctx, c.ID, nil, nil, nil, nil, nil, nil, at(10))
+	requireNoError(t, err)
+
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		filter models.ElectionFilter
+		want   []string
+	}{
+		{"created_at desc by default", 10, 0, models.ElectionFilter{}, []string{a.ID, c.ID, b.ID}},
+		{"created_at asc", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortCreatedAt, SortOrder: models.SortOrderAsc}, []string{b.ID, c.ID, a.ID}},
+		{"name asc", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortName, SortOrder: models.SortOrderAsc}, []string{a.ID, b.ID, c.ID}},
+		{"name desc", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortName}, []string{c.ID, b.ID, a.ID}},
+		{"updated_at desc", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortUpdatedAt}, []string{c.ID, a.ID, b.ID}},
+		{"relevance", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortRelevance, Query: "pizza"}, []string{c.ID, a.ID, b.ID}},
+		{"relevance without query", 10, 0, models.ElectionFilter{SortBy: models.ElectionSortRelevance}, []string{a.ID, c.ID, b.ID}},
+		{"first page", 2, 0, models.ElectionFilter{}, []string{a.ID, c.ID}},
+		{"last page", 2, 2, models.ElectionFilter{}, []string{b.ID}},
+		{"offset past the end", 2, 3, models.ElectionFilter{}, nil},
+		{"zero limit", 0, 0, models.ElectionFilter{}, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.GetElections(ctx, tt.limit, tt.offset, tt.filter)
+		requireNoError(t, err)
+		requireIDs(t, tt.name, ids(got, electionID), tt.want...)
+	}
+}
+
+func testElectionPatch(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(0))
+	election := newElection(t, s, alice.ID, "Lunch", "Where to eat", at(1))
+
+	patched, err := s.PatchElection(ctx, election.ID,
+		ptr(bob.ID),
+		ptr("Dinner"),
+		ptr("Where to dine"),
+		ptr(models.ElectionStatusClosed),
+		ptr(models.VotePolicyFinal),
+		ptr(int64(1)),
+		at(2))
+	requireNoError(t, err)
+	requireEqual(t, "user_id", patched.UserID, bob.ID)
+	requireEqual(t, "name", patched.Name, "Dinner")
+	requireEqual(t, "description", patched.Description, "Where to dine")
+	requireEqual(t, "status", patched.Status, models.ElectionStatusClosed)
+	requireEqual(t, "vote_policy", patched.VotePolicy, models.VotePolicyFinal)
+	requireEqual(t, "version", patched.Version, 2)
+	requireTime(t, "created_at", patched.CreatedAt, at(1))
+	requireTime(t, "updated_at", patched.UpdatedAt, at(2))
+
+	// Не переданные поля не меняются
+	patched, err = s.PatchElection(ctx, election.ID, nil, ptr("Supper"), nil, nil, nil, nil, at(3))
+	requireNoError(t, err)
+	requireEqual(t, "name", patched.Name, "Supper")
+	requireEqual(t, "description", patched.Description, "Where to dine")
+	requireEqual(t, "status", patched.Status, models.ElectionStatusClosed)
+	requireEqual(t, "version", patched.Version, 3)
+
+	_, err = s.PatchElection(ctx, election.ID, nil, ptr("Brunch"), nil, nil, nil, ptr(int64(2)), at(4))
+	requireError(t, err, apperrors.ErrVersionConflict)
+
+	_, err = s.PatchElection(ctx, election.ID, ptr(uuid.NewString()), nil, nil, nil, nil, nil, at(4))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	// Мягко удаленный пользователь не может стать владельцем
+	carol := newUser(t, s, "carol", at(0))
+	requireNoError(t, s.DeleteUser(ctx, carol.ID, nil, at(4)))
+	_, err = s.PatchElection(ctx, election.ID, ptr(carol.ID), nil, nil, nil, nil, ptr(int64(3)), at(4))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	_, err = s.PatchElection(ctx, uuid.NewString(), nil, ptr("Brunch"), nil, nil, nil, nil, at(4))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	got, err := s.GetElection(ctx, election.ID)
+	requireNoError(t, err)
+	requireEqual(t, "name after failed patches", got.Name, "Supper")
+	requireEqual(t, "version after failed patches", got.Version, 3)
+}
+
+func testElectionDeleteRestorePurge(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(1))
+
+	requireError(t, s.DeleteElection(ctx, election.ID, ptr(int64(2)), at(2)), apperrors.ErrVersionConflict)
+	requireNoError(t, s.DeleteElection(ctx, election.ID, ptr(int64(1)), at(2)))
+	requireError(t, s.DeleteElection(ctx, election.ID, nil, at(3)), apperrors.ErrElectionNotFound)
+	requireError(t, s.DeleteElection(ctx, uuid.NewString(), nil, at(3)), apperrors.ErrElectionNotFound)
+
+	_, err := s.GetElection(ctx, election.ID)
+	requireError(t, err, apperrors.ErrElectionNotFound)
+	_, err = s.PatchElection(ctx, election.ID, nil, ptr("Dinner"), nil, nil, nil, nil, at(3))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	restored, err := s.RestoreElection(ctx, election.ID, at(4))
+	requireNoError(t, err)
+	requireEqual(t, "version", restored.Version, 3)
+	requireTime(t, "updated_at", restored.UpdatedAt, at(4))
+
+	_, err = s.RestoreElection(ctx, election.ID, at(5))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	requireNoError(t, s.DeleteElection(ctx, election.ID, nil, at(6)))
+
+	purged, err := s.PurgeElections(ctx, at(6))
+	requireNoError(t, err)
+	requireEqual(t, "purged before deletion", purged, 0)
+
+	purged, err = s.PurgeElections(ctx, at(7))
+	requireNoError(t, err)
+	requireEqual(t, "purged", purged, 1)
+
+	_, err = s.RestoreElection(ctx, election.ID, at(8))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	// Варианты окончательно удаленных выборов удаляются вместе с ними
+	_, err = s.GetVoteVariant(ctx, voteVariant.ID)
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+}
@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/service"
+)
+
+var idempotencyCases = []testCase{
+	{"claim and save", testIdempotencyClaimAndSave},
+	{"expiration", testIdempotencyExpiration},
+}
+
+func testIdempotencyClaimAndSave(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	const key = "user-1:create-election"
+
+	claimed, err := s.ClaimIdempotencyKey(ctx, key, "hash-1", at(0), at(60))
+	requireNoError(t, err)
+	requireEqual(t, "first claim", claimed, true)
+
+	claimed, err = s.ClaimIdempotencyKey(ctx, key, "hash-2", at(1), at(61))
+	requireNoError(t, err)
+	requireEqual(t, "second claim", claimed, false)
+
+	// Запрос еще выполняется
+	record, err := s.GetIdempotencyRecord(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "key", record.Key, key)
+	requireEqual(t, "request_hash", record.RequestHash, "hash-1")
+	requireEqual(t, "status_code", record.StatusCode, 0)
+	requireLen(t, "body", record.Body, 0)
+	requireTime(t, "created_at", record.CreatedAt, at(0))
+	requireTime(t, "expires_at", record.ExpiresAt, at(60))
+
+	requireNoError(t, s.SaveIdempotencyResponse(ctx, key, 201, `"1"`, "/golos/elections/1", []byte(`{"id":"1"}`)))
+	record, err = s.GetIdempotencyRecord(ctx, key)
+	requireNoError(t, err)
+	requireEqual(t, "status_code", record.StatusCode, 201)
+	requireEqual(t, "etag", record.ETag, `"1"`)
+	requireEqual(t, "location", record.Location, "/golos/elections/1")
+	requireEqual(t, "body", string(record.Body), `{"id":"1"}`)
+
+	requireError(t, s.SaveIdempotencyResponse(ctx, "unknown", 200, "", "", nil), apperrors.ErrIdempotencyKeyNotFound)
+	_, err = s.GetIdempotencyRecord(ctx, "unknown")
+	requireError(t, err, apperrors.ErrIdempotencyKeyNotFound)
+
+	requireNoError(t, s.DeleteIdempotencyKey(ctx, key))
+	_, err = s.GetIdempotencyRecord(ctx, key)
+	requireError(t, err, apperrors.ErrIdempotencyKeyNotFound)
+	requireNoError(t, s.DeleteIdempotencyKey(ctx, key))
+
+	// Освобожденный ключ можно занять снова
+	claimed, err = s.ClaimIdempotencyKey(ctx, key, "hash-3", at(2), at(62))
+	requireNoError(t, err)
+	requireEqual(t, "claim after delete", claimed, true)
+}
+
+func testIdempotencyExpiration(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+
+	_, err := s.ClaimIdempotencyKey(ctx, "expired", "hash-1", at(0), at(10))
+	requireNoError(t, err)
+	requireNoError(t, s.SaveIdempotencyResponse(ctx, "expired", 200, `"3"`, "", []byte("ok")))
+	_, err = s.ClaimIdempotencyKey(ctx, "alive", "hash-1", at(0), at(30))
+	requireNoError(t, err)
+
+	// Истекший ключ занимается заново с чистым ответом
+	claimed, err := s.ClaimIdempotencyKey(ctx, "expired", "hash-2", at(11), at(21))
+	requireNoError(t, err)
+	requireEqual(t, "claim after expiration", claimed, true)
+
+	record, err := s.GetIdempotencyRecord(ctx, "expired")
+	requireNoError(t, err)
+	requireEqual(t, "request_hash", record.RequestHash, "hash-2")
+	requireEqual(t, "status_code", record.StatusCode, 0)
+	requireEqual(t, "etag", record.ETag, "")
+	requireLen(t, "body", record.Body, 0)
+	requireTime(t, "created_at", record.CreatedAt, at(11))
+	requireTime(t, "expires_at", record.ExpiresAt, at(21))
+
+	deleted, err := s.DeleteExpiredIdempotencyKeys(ctx, at(21))
+	requireNoError(t, err)
+	requireEqual(t, "deleted at expiration", deleted, 0)
+
+	deleted, err = s.DeleteExpiredIdempotencyKeys(ctx, at(22))
+	requireNoError(t, err)
+	requireEqual(t, "deleted", deleted, 1)
+
+	_, err = s.GetIdempotencyRecord(ctx, "expired")
+	requireError(t, err, apperrors.ErrIdempotencyKeyNotFound)
+	_, err = s.GetIdempotencyRecord(ctx, "alive")
+	requireNoError(t, err)
+}
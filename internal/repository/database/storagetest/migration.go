@@ -0,0 +1,82 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+// Строки из схемы до индекса (user_id, election_id), когда пользователь
+// мог голосовать в одних выборах за несколько вариантов
+type LegacyVotes struct {
+	Users        []*models.User
+	Elections    []*models.Election
+	VoteVariants []*models.VoteVariant
+	Votes        []*models.Vote
+}
+
+// Хранилище, мигрированное до версии без индекса, с записанными строками seed
+// и затем доведенное до последней миграции. Бэкенд вставляет строки своим SQL
+type OpenLegacy func(t *testing.T, seed LegacyVotes) service.Storage
+
+// Миграция оставляет последний голос пользователя в выборах,
+// а остальные отзывает с записью в истории
+func RunDuplicateVotes(t *testing.T, open OpenLegacy) {
+	ctx := t.Context()
+
+	alice := &models.User{ID: uuid.NewString(), Nickname: "alice", Password: "hash", CreatedAt: at(0), UpdatedAt: at(0)}
+	bob := &models.User{ID: uuid.NewString(), Nickname: "bob", Password: "hash", CreatedAt: at(0), UpdatedAt: at(0)}
+	lunch := &models.Election{ID: uuid.NewString(), UserID: alice.ID, Name: "Lunch", Description: "Where to eat", CreatedAt: at(1), UpdatedAt: at(1)}
+	dinner := &models.Election{ID: uuid.NewString(), UserID: alice.ID, Name: "Dinner", Description: "Where to dine", CreatedAt: at(1), UpdatedAt: at(1)}
+	pizza := &models.VoteVariant{ID: uuid.NewString(), ElectionID: lunch.ID, Name: "Pizza", CreatedAt: at(2), UpdatedAt: at(2)}
+	sushi := &models.VoteVariant{ID: uuid.NewString(), ElectionID: lunch.ID, Name: "Sushi", CreatedAt: at(2), UpdatedAt: at(2)}
+	burger := &models.VoteVariant{ID: uuid.NewString(), ElectionID: lunch.ID, Name: "Burger", CreatedAt: at(2), UpdatedAt: at(2)}
+	pasta := &models.VoteVariant{ID: uuid.NewString(), ElectionID: dinner.ID, Name: "Pasta", CreatedAt: at(2), UpdatedAt: at(2)}
+
+	vote := func(user *models.User, variant *models.VoteVariant, minutes int) *models.Vote {
+		return &models.Vote{ID: uuid.NewString(), UserID: user.ID, VariantID: variant.ID, CreatedAt: at(minutes), UpdatedAt: at(minutes)}
+	}
+	alicePizza := vote(alice, pizza, 3)
+	aliceSushi := vote(alice, sushi, 4)
+	aliceBurger := vote(alice, burger, 5)
+	alicePasta := vote(alice, pasta, 3)
+	bobPizza := vote(bob, pizza, 3)
+
+	s := open(t, LegacyVotes{
+		Users:        []*models.User{alice, bob},
+		Elections:    []*models.Election{lunch, dinner},
+		VoteVariants: []*models.VoteVariant{pizza, sushi, burger, pasta},
+		Votes:        []*models.Vote{alicePizza, aliceBurger, aliceSushi, alicePasta, bobPizza},
+	})
+
+	// Из дубликатов остается последний поданный голос, голоса в других выборах не трогаются
+	votes, err := s.GetUserVotes(ctx, alice.ID, nil, 10, 0)
+	requireNoError(t, err)
+	requireIDs(t, "alice votes", ids(votes, func(v *models.Vote) string { return v.ID }), aliceBurger.ID, alicePasta.ID)
+
+	votes, err = s.GetUserVotes(ctx, bob.ID, nil, 10, 0)
+	requireNoError(t, err)
+	requireIDs(t, "bob votes", ids(votes, func(v *models.Vote) string { return v.ID }), bobPizza.ID)
+
+	for _, dropped := range []*models.Vote{alicePizza, aliceSushi} {
+		_, err := s.GetVote(ctx, dropped.ID)
+		requireError(t, err, apperrors.ErrVoteNotFound)
+
+		history, err := s.GetVoteHistory(ctx, dropped.ID)
+		requireNoError(t, err)
+		if len(history) == 0 {
+			t.Fatalf("history of dropped vote %s is empty", dropped.ID)
+		}
+		withdrawal := history[len(history)-1]
+		requireEqual(t, "withdrawal user_id", withdrawal.UserID, alice.ID)
+		requireEqual(t, "withdrawal old_variant_id", withdrawal.OldVariantID, dropped.VariantID)
+		requireEqual(t, "withdrawal new_variant_id", withdrawal.NewVariantID, "")
+	}
+
+	// После миграции действует правило одного голоса на выборы
+	_, err = s.CreateVote(ctx, uuid.NewString(), alice.ID, pizza.ID, at(6), at(6))
+	requireError(t, err, apperrors.ErrVoteAlreadyExist)
+}
@@ -0,0 +1,145 @@
+// Package storagetest - общий набор проверок контракта service.Storage.
+// Каждый бэкенд вызывает Run из своего _test.go, поэтому postgres, sqlite и memory
+// проверяются одними и теми же сценариями и возвращают одни и те же apperrors
+package storagetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+// Пустое хранилище для одного сценария, закрывается через t.Cleanup
+type Open func(t *testing.T) service.Storage
+
+// Сценарий получает свое пустое хранилище
+type testCase struct {
+	name string
+	fn   func(t *testing.T, s service.Storage)
+}
+
+// Время без наносекунд: postgres хранит TIMESTAMP с точностью до микросекунд
+var base = time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+func Run(t *testing.T, open Open) {
+	groups := []struct {
+		name  string
+		cases []testCase
+	}{
+		{"users", userCases},
+		{"elections", electionCases},
+		{"vote_variants", voteVariantCases},
+		{"votes", voteCases},
+		{"translations", translationCases},
+		{"auth", authCases},
+		{"idempotency", idempotencyCases},
+		{"audit", auditCases},
+	}
+
+	for _, group := range groups {
+		t.Run(group.name, func(t *testing.T) {
+			for _, tc := range group.cases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.fn(t, open(t))
+				})
+			}
+		})
+	}
+}
+
+// Момент через minutes минут после base
+func at(minutes int) time.Time {
+	return base.Add(time.Duration(minutes) * time.Minute)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func requireNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func requireError(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("expected error %v, got %v", target, err)
+	}
+}
+
+func requireTime(t *testing.T, field string, got, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Fatalf("%s: expected %v, got %v", field, want, got)
+	}
+}
+
+func requireEqual[T comparable](t *testing.T, field string, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s: expected %v, got %v", field, want, got)
+	}
+}
+
+func requireLen[T any](t *testing.T, field string, items []T, want int) {
+	t.Helper()
+	if len(items) != want {
+		t.Fatalf("%s: expected %d items, got %d", field, want, len(items))
+	}
+}
+
+// ID элементов в порядке выдачи
+func ids[T any](items []*T, id func(*T) string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, id(item))
+	}
+	return result
+}
+
+func requireIDs(t *testing.T, field string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", field, want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected %v, got %v", field, want, got)
+		}
+	}
+}
+
+func newUser(t *testing.T, s service.Storage, nickname string, createdAt time.Time) *models.User {
+	t.Helper()
+	user, err := s.CreateUser(t.Context(), uuid.NewString(), nickname, "hash", createdAt, createdAt)
+	requireNoError(t, err)
+	return user
+}
+
+func newElection(t *testing.T, s service.Storage, userID, name, description string, createdAt time.Time) *models.Election {
+	t.Helper()
+	election, err := s.CreateElection(t.Context(), uuid.NewString(), userID, name, description, models.VotePolicyChangesUntilClose, createdAt, createdAt)
+	requireNoError(t, err)
+	return election
+}
+
+func newVoteVariant(t *testing.T, s service.Storage, electionID, name string, createdAt time.Time) *models.VoteVariant {
+	t.Helper()
+	voteVariant, err := s.CreateVoteVariant(t.Context(), uuid.NewString(), electionID, name, createdAt, createdAt)
+	requireNoError(t, err)
+	return voteVariant
+}
+
+func newVote(t *testing.T, s service.Storage, userID, voteVariantID string, createdAt time.Time) *models.Vote {
+	t.Helper()
+	vote, err := s.CreateVote(t.Context(), uuid.NewString(), userID, voteVariantID, createdAt, createdAt)
+	requireNoError(t, err)
+	return vote
+}
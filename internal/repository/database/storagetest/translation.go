@@ -0,0 +1,122 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var translationCases = []testCase{
+	{"election translations", testElectionTranslations},
+	{"vote variant translations", testVoteVariantTranslations},
+}
+
+func testElectionTranslations(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	lunch := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	dinner := newElection(t, s, user.ID, "Dinner", "Where to dine", at(1))
+
+	translation, err := s.SetElectionTranslation(ctx, lunch.ID, "ru", "Обед", "Где поесть", at(2))
+	requireNoError(t, err)
+	requireEqual(t, "election_id", translation.ElectionID, lunch.ID)
+	requireEqual(t, "locale", translation.Locale, "ru")
+	requireEqual(t, "name", translation.Name, "Обед")
+	requireEqual(t, "description", translation.Description, "Где поесть")
+	requireTime(t, "created_at", translation.CreatedAt, at(2))
+	requireTime(t, "updated_at", translation.UpdatedAt, at(2))
+
+	// Повторная установка обновляет перевод, created_at сохраняется
+	translation, err = s.SetElectionTranslation(ctx, lunch.ID, "ru", "Ланч", "Где пообедать", at(3))
+	requireNoError(t, err)
+	requireEqual(t, "name", translation.Name, "Ланч")
+	requireEqual(t, "description", translation.Description, "Где пообедать")
+	requireTime(t, "created_at", translation.CreatedAt, at(2))
+	requireTime(t, "updated_at", translation.UpdatedAt, at(3))
+
+	_, err = s.SetElectionTranslation(ctx, lunch.ID, "en", "Lunch", "Where to eat", at(4))
+	requireNoError(t, err)
+	_, err = s.SetElectionTranslation(ctx, dinner.ID, "ru", "Ужин", "Где поужинать", at(4))
+	requireNoError(t, err)
+
+	_, err = s.SetElectionTranslation(ctx, uuid.NewString(), "ru", "Обед", "Где поесть", at(5))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	key := func(tr *models.ElectionTranslation) string { return tr.ElectionID + "/" + tr.Locale }
+
+	got, err := s.GetElectionTranslations(ctx, []string{lunch.ID}, "")
+	requireNoError(t, err)
+	requireIDs(t, "all locales", ids(got, key), lunch.ID+"/en", lunch.ID+"/ru")
+
+	got, err = s.GetElectionTranslations(ctx, []string{lunch.ID, dinner.ID}, "ru")
+	requireNoError(t, err)
+	requireLen(t, "one locale", got, 2)
+	for _, tr := range got {
+		requireEqual(t, "locale", tr.Locale, "ru")
+	}
+
+	got, err = s.GetElectionTranslations(ctx, []string{uuid.NewString()}, "")
+	requireNoError(t, err)
+	requireLen(t, "unknown election", got, 0)
+
+	requireNoError(t, s.DeleteElectionTranslation(ctx, lunch.ID, "ru"))
+	requireError(t, s.DeleteElectionTranslation(ctx, lunch.ID, "ru"), apperrors.ErrTranslationNotFound)
+	requireError(t, s.DeleteElectionTranslation(ctx, uuid.NewString(), "en"), apperrors.ErrTranslationNotFound)
+
+	got, err = s.GetElectionTranslations(ctx, []string{lunch.ID}, "")
+	requireNoError(t, err)
+	requireIDs(t, "after delete", ids(got, key), lunch.ID+"/en")
+}
+
+func testVoteVariantTranslations(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	pizza := newVoteVariant(t, s, election.ID, "Pizza", at(1))
+	sushi := newVoteVariant(t, s, election.ID, "Sushi", at(1))
+
+	translation, err := s.SetVoteVariantTranslation(ctx, pizza.ID, "ru", "Пицца", at(2))
+	requireNoError(t, err)
+	requireEqual(t, "vote_variant_id", translation.VoteVariantID, pizza.ID)
+	requireEqual(t, "locale", translation.Locale, "ru")
+	requireEqual(t, "name", translation.Name, "Пицца")
+	requireTime(t, "created_at", translation.CreatedAt, at(2))
+	requireTime(t, "updated_at", translation.UpdatedAt, at(2))
+
+	translation, err = s.SetVoteVariantTranslation(ctx, pizza.ID, "ru", "Пиццу", at(3))
+	requireNoError(t, err)
+	requireEqual(t, "name", translation.Name, "Пиццу")
+	requireTime(t, "created_at", translation.CreatedAt, at(2))
+	requireTime(t, "updated_at", translation.UpdatedAt, at(3))
+
+	_, err = s.SetVoteVariantTranslation(ctx, pizza.ID, "en", "Pizza", at(4))
+	requireNoError(t, err)
+	_, err = s.SetVoteVariantTranslation(ctx, sushi.ID, "ru", "Суши", at(4))
+	requireNoError(t, err)
+
+	_, err = s.SetVoteVariantTranslation(ctx, uuid.NewString(), "ru", "Паста", at(5))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	key := func(tr *models.VoteVariantTranslation) string { return tr.VoteVariantID + "/" + tr.Locale }
+
+	got, err := s.GetVoteVariantTranslations(ctx, []string{pizza.ID}, "")
+	requireNoError(t, err)
+	requireIDs(t, "all locales", ids(got, key), pizza.ID+"/en", pizza.ID+"/ru")
+
+	got, err = s.GetVoteVariantTranslations(ctx, []string{pizza.ID, sushi.ID}, "ru")
+	requireNoError(t, err)
+	requireLen(t, "one locale", got, 2)
+	for _, tr := range got {
+		requireEqual(t, "locale", tr.Locale, "ru")
+	}
+
+	requireNoError(t, s.DeleteVoteVariantTranslation(ctx, pizza.ID, "ru"))
+	requireError(t, s.DeleteVoteVariantTranslation(ctx, pizza.ID, "ru"), apperrors.ErrTranslationNotFound)
+
+	got, err = s.GetVoteVariantTranslations(ctx, []string{pizza.ID}, "")
+	requireNoError(t, err)
+	requireIDs(t, "after delete", ids(got, key), pizza.ID+"/en")
+}
@@ -0,0 +1,198 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var userCases = []testCase{
+	{"create and get", testUserCreateAndGet},
+	{"duplicate nickname", testUserDuplicateNickname},
+	{"pagination", testUserPagination},
+	{"update and patch", testUserUpdateAndPatch},
+	{"delete, restore and purge", testUserDeleteRestorePurge},
+}
+
+func testUserCreateAndGet(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	id := uuid.NewString()
+
+	user, err := s.CreateUser(ctx, id, "alice", "hash", at(0), at(1))
+	requireNoError(t, err)
+	requireEqual(t, "id", user.ID, id)
+	requireEqual(t, "nickname", user.Nickname, "alice")
+	requireEqual(t, "password", user.Password, "hash")
+	requireTime(t, "created_at", user.CreatedAt, at(0))
+	requireTime(t, "updated_at", user.UpdatedAt, at(1))
+	requireEqual(t, "version", user.Version, 1)
+
+	got, err := s.GetUser(ctx, id)
+	requireNoError(t, err)
+	requireEqual(t, "nickname", got.Nickname, "alice")
+	requireTime(t, "created_at", got.CreatedAt, at(0))
+
+	got, err = s.GetUserByNickname(ctx, "alice")
+	requireNoError(t, err)
+	requireEqual(t, "id", got.ID, id)
+
+	_, err = s.GetUser(ctx, uuid.NewString())
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	_, err = s.GetUserByNickname(ctx, "bob")
+	requireError(t, err, apperrors.ErrUserNotFound)
+}
+
+func testUserDuplicateNickname(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(1))
+
+	_, err := s.CreateUser(ctx, uuid.NewString(), "alice", "hash", at(2), at(2))
+	requireError(t, err, apperrors.ErrUserAlreadyExist)
+
+	_, err = s.PatchUser(ctx, bob.ID, ptr("alice"), nil, at(2))
+	requireError(t, err, apperrors.ErrUserAlreadyExist)
+
+	_, err = s.UpdateUser(ctx, bob.ID, "alice", nil, at(2))
+	requireError(t, err, apperrors.ErrUserAlreadyExist)
+
+	// Ник удаленного пользователя свободен, но вернуть его уже нельзя
+	requireNoError(t, s.DeleteUser(ctx, alice.ID, nil, at(3)))
+	newUser(t, s, "alice", at(4))
+
+	_, err = s.RestoreUser(ctx, alice.ID, at(5))
+	requireError(t, err, apperrors.ErrUserAlreadyExist)
+}
+
+func testUserPagination(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+
+	var users []*models.User
+	for i, nickname := range []string{"user1", "user2", "user3", "user4", "user5"} {
+		users = append(users, newUser(t, s, nickname, at(i)))
+	}
+	requireNoError(t, s.DeleteUser(ctx, users[2].ID, nil, at(10)))
+
+	userID := func(u *models.User) string { return u.ID }
+
+	// Сначала новые, удаленные не попадают
+	got, err := s.GetUsers(ctx, 10, 0)
+	requireNoError(t, err)
+	requireIDs(t, "all", ids(got, userID), users[4].ID, users[3].ID, users[1].ID, users[0].ID)
+
+	got, err = s.GetUsers(ctx, 2, 0)
+	requireNoError(t, err)
+	requireIDs(t, "first page", ids(got, userID), users[4].ID, users[3].ID)
+
+	got, err = s.GetUsers(ctx, 2, 2)
+	requireNoError(t, err)
+	requireIDs(t, "second page", ids(got, userID), users[1].ID, users[0].ID)
+
+	got, err = s.GetUsers(ctx, 2, 3)
+	requireNoError(t, err)
+	requireIDs(t, "partial page", ids(got, userID), users[0].ID)
+
+	got, err = s.GetUsers(ctx, 2, 4)
+	requireNoError(t, err)
+	requireLen(t, "offset past the end", got, 0)
+
+	got, err = s.GetUsers(ctx, 0, 0)
+	requireNoError(t, err)
+	requireLen(t, "zero limit", got, 0)
+}
+
+func testUserUpdateAndPatch(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+
+	updated, err := s.UpdateUser(ctx, user.ID, "alice2", ptr(int64(1)), at(1))
+	requireNoError(t, err)
+	requireEqual(t, "nickname", updated.Nickname, "alice2")
+	requireEqual(t, "version", updated.Version, 2)
+	requireTime(t, "created_at", updated.CreatedAt, at(0))
+	requireTime(t, "updated_at", updated.UpdatedAt, at(1))
+
+	_, err = s.UpdateUser(ctx, user.ID, "alice3", ptr(int64(1)), at(2))
+	requireError(t, err, apperrors.ErrVersionConflict)
+
+	// Без полей меняются только updated_at и версия
+	patched, err := s.PatchUser(ctx, user.ID, nil, ptr(int64(2)), at(3))
+	requireNoError(t, err)
+	requireEqual(t, "nickname", patched.Nickname, "alice2")
+	requireEqual(t, "version", patched.Version, 3)
+	requireTime(t, "updated_at", patched.UpdatedAt, at(3))
+
+	_, err = s.PatchUser(ctx, user.ID, ptr("alice4"), ptr(int64(2)), at(4))
+	requireError(t, err, apperrors.ErrVersionConflict)
+
+	patched, err = s.PatchUser(ctx, user.ID, ptr("alice4"), nil, at(4))
+	requireNoError(t, err)
+	requireEqual(t, "nickname", patched.Nickname, "alice4")
+	requireEqual(t, "version", patched.Version, 4)
+
+	requireNoError(t, s.UpdateUserPassword(ctx, user.ID, "new-hash", at(5)))
+	got, err := s.GetUser(ctx, user.ID)
+	requireNoError(t, err)
+	requireEqual(t, "password", got.Password, "new-hash")
+	requireEqual(t, "version", got.Version, 5)
+	requireTime(t, "updated_at", got.UpdatedAt, at(5))
+
+	missing := uuid.NewString()
+	_, err = s.UpdateUser(ctx, missing, "carol", nil, at(6))
+	requireError(t, err, apperrors.ErrUserNotFound)
+	_, err = s.PatchUser(ctx, missing, nil, ptr(int64(1)), at(6))
+	requireError(t, err, apperrors.ErrUserNotFound)
+	requireError(t, s.UpdateUserPassword(ctx, missing, "hash", at(6)), apperrors.ErrUserNotFound)
+}
+
+func testUserDeleteRestorePurge(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+
+	requireError(t, s.DeleteUser(ctx, user.ID, ptr(int64(2)), at(2)), apperrors.ErrVersionConflict)
+	requireNoError(t, s.DeleteUser(ctx, user.ID, ptr(int64(1)), at(2)))
+
+	_, err := s.GetUser(ctx, user.ID)
+	requireError(t, err, apperrors.ErrUserNotFound)
+	_, err = s.GetUserByNickname(ctx, "alice")
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	// Удаленная строка не найдена и для изменений, даже с версией
+	requireError(t, s.DeleteUser(ctx, user.ID, nil, at(3)), apperrors.ErrUserNotFound)
+	requireError(t, s.DeleteUser(ctx, user.ID, ptr(int64(2)), at(3)), apperrors.ErrUserNotFound)
+	_, err = s.PatchUser(ctx, user.ID, ptr("bob"), ptr(int64(2)), at(3))
+	requireError(t, err, apperrors.ErrUserNotFound)
+	requireError(t, s.UpdateUserPassword(ctx, user.ID, "hash", at(3)), apperrors.ErrUserNotFound)
+
+	restored, err := s.RestoreUser(ctx, user.ID, at(4))
+	requireNoError(t, err)
+	requireEqual(t, "version", restored.Version, 3)
+	requireTime(t, "updated_at", restored.UpdatedAt, at(4))
+
+	_, err = s.RestoreUser(ctx, user.ID, at(5))
+	requireError(t, err, apperrors.ErrUserNotFound)
+	_, err = s.RestoreUser(ctx, uuid.NewString(), at(5))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	requireNoError(t, s.DeleteUser(ctx, user.ID, nil, at(6)))
+
+	purged, err := s.PurgeUsers(ctx, at(6))
+	requireNoError(t, err)
+	requireEqual(t, "purged before deletion", purged, 0)
+
+	purged, err = s.PurgeUsers(ctx, at(7))
+	requireNoError(t, err)
+	requireEqual(t, "purged", purged, 1)
+
+	_, err = s.RestoreUser(ctx, user.ID, at(8))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	// Выборы окончательно удаленного пользователя удаляются вместе с ним
+	_, err = s.GetElection(ctx, election.ID)
+	requireError(t, err, apperrors.ErrElectionNotFound)
+}
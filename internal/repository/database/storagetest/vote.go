@@ -0,0 +1,309 @@
+package storagetest
+
+import (
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var voteCases = []testCase{
+	{"create and get", testVoteCreateAndGet},
+	{"foreign keys", testVoteForeignKeys},
+	{"deleted parents", testVoteDeletedParents},
+	{"patch", testVotePatch},
+	{"user votes", testVoteUserVotes},
+	{"election results", testVoteElectionResults},
+	{"delete", testVoteDelete},
+}
+
+func voteID(v *models.Vote) string { return v.ID }
+
+func testVoteCreateAndGet(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	id := uuid.NewString()
+
+	vote, err := s.CreateVote(ctx, id, user.ID, voteVariant.ID, at(3), at(4))
+	requireNoError(t, err)
+	requireEqual(t, "id", vote.ID, id)
+	requireEqual(t, "user_id", vote.UserID, user.ID)
+	requireEqual(t, "variant_id", vote.VariantID, voteVariant.ID)
+	requireTime(t, "created_at", vote.CreatedAt, at(3))
+	requireTime(t, "updated_at", vote.UpdatedAt, at(4))
+
+	got, err := s.GetVote(ctx, id)
+	requireNoError(t, err)
+	requireEqual(t, "variant_id", got.VariantID, voteVariant.ID)
+
+	_, err = s.GetVote(ctx, uuid.NewString())
+	requireError(t, err, apperrors.ErrVoteNotFound)
+
+	// Первоначальный голос сразу попадает в историю
+	history, err := s.GetVoteHistory(ctx, id)
+	requireNoError(t, err)
+	requireLen(t, "history", history, 1)
+	requireEqual(t, "vote_id", history[0].VoteID, id)
+	requireEqual(t, "user_id", history[0].UserID, user.ID)
+	requireEqual(t, "old_variant_id", history[0].OldVariantID, "")
+	requireEqual(t, "new_variant_id", history[0].NewVariantID, voteVariant.ID)
+	requireTime(t, "changed_at", history[0].ChangedAt, at(3))
+
+	_, err = s.CreateVote(ctx, uuid.NewString(), user.ID, voteVariant.ID, at(5), at(5))
+	requireError(t, err, apperrors.ErrVoteAlreadyExist)
+
+	// Один голос на выборы, даже за другой вариант
+	other := newVoteVariant(t, s, election.ID, "Sushi", at(2))
+	_, err = s.CreateVote(ctx, uuid.NewString(), user.ID, other.ID, at(5), at(5))
+	requireError(t, err, apperrors.ErrVoteAlreadyExist)
+
+	_, err = s.GetVariantVotes(ctx, voteVariant.ID)
+	requireNoError(t, err)
+}
+
+func testVoteForeignKeys(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+
+	_, err := s.CreateVote(ctx, uuid.NewString(), uuid.NewString(), voteVariant.ID, at(3), at(3))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	_, err = s.CreateVote(ctx, uuid.NewString(), user.ID, uuid.NewString(), at(3), at(3))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	// Неудачная вставка не оставляет ни голоса, ни истории
+	votes, err := s.GetUserVotes(ctx, user.ID, nil, 10, 0)
+	requireNoError(t, err)
+	requireLen(t, "votes", votes, 0)
+
+	vote := newVote(t, s, user.ID, voteVariant.ID, at(4))
+
+	_, err = s.PatchVote(ctx, vote.ID, ptr(uuid.NewString()), nil, at(5))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	_, err = s.PatchVote(ctx, vote.ID, nil, ptr(uuid.NewString()), at(5))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	got, err := s.GetVote(ctx, vote.ID)
+	requireNoError(t, err)
+	requireEqual(t, "user_id after failed patch", got.UserID, user.ID)
+	requireEqual(t, "variant_id after failed patch", got.VariantID, voteVariant.ID)
+
+	history, err := s.GetVoteHistory(ctx, vote.ID)
+	requireNoError(t, err)
+	requireLen(t, "history after failed patch", history, 1)
+}
+
+// Внешние ключи пропускают мягко удаленных родителей, хранилище проверяет их само
+func testVoteDeletedParents(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	deletedUser := newUser(t, s, "bob", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	deletedElection := newElection(t, s, user.ID, "Dinner", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	deletedVoteVariant := newVoteVariant(t, s, election.ID, "Sushi", at(2))
+	orphanVoteVariant := newVoteVariant(t, s, deletedElection.ID, "Pasta", at(2))
+
+	requireNoError(t, s.DeleteUser(ctx, deletedUser.ID, nil, at(3)))
+	requireNoError(t, s.DeleteVoteVariant(ctx, deletedVoteVariant.ID, nil, at(3)))
+	requireNoError(t, s.DeleteElection(ctx, deletedElection.ID, nil, at(3)))
+
+	_, err := s.CreateVote(ctx, uuid.NewString(), deletedUser.ID, voteVariant.ID, at(4), at(4))
+	requireError(t, err, apperrors.ErrUserNotFound)
+
+	_, err = s.CreateVote(ctx, uuid.NewString(), user.ID, deletedVoteVariant.ID, at(4), at(4))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	_, err = s.CreateVote(ctx, uuid.NewString(), user.ID, orphanVoteVariant.ID, at(4), at(4))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	votes, err := s.GetUserVotes(ctx, user.ID, nil, 10, 0)
+	requireNoError(t, err)
+	requireLen(t, "votes", votes, 0)
+}
+
+func testVotePatch(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(0))
+	election := newElection(t, s, alice.ID, "Lunch", "Where to eat", at(1))
+	pizza := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	sushi := newVoteVariant(t, s, election.ID, "Sushi", at(2))
+	vote := newVote(t, s, alice.ID, pizza.ID, at(3))
+
+	patched, err := s.PatchVote(ctx, vote.ID, nil, ptr(sushi.ID), at(4))
+	requireNoError(t, err)
+	requireEqual(t, "user_id", patched.UserID, alice.ID)
+	requireEqual(t, "variant_id", patched.VariantID, sushi.ID)
+	requireTime(t, "created_at", patched.CreatedAt, at(3))
+	requireTime(t, "updated_at", patched.UpdatedAt, at(4))
+
+	// Без смены варианта история не пополняется
+	patched, err = s.PatchVote(ctx, vote.ID, nil, ptr(sushi.ID), at(5))
+	requireNoError(t, err)
+	requireTime(t, "updated_at", patched.UpdatedAt, at(5))
+
+	patched, err = s.PatchVote(ctx, vote.ID, nil, nil, at(6))
+	requireNoError(t, err)
+	requireEqual(t, "variant_id", patched.VariantID, sushi.ID)
+	requireTime(t, "updated_at", patched.UpdatedAt, at(6))
+
+	patched, err = s.PatchVote(ctx, vote.ID, ptr(bob.ID), ptr(pizza.ID), at(7))
+	requireNoError(t, err)
+	requireEqual(t, "user_id", patched.UserID, bob.ID)
+	requireEqual(t, "variant_id", patched.VariantID, pizza.ID)
+
+	history, err := s.GetVoteHistory(ctx, vote.ID)
+	requireNoError(t, err)
+	requireLen(t, "history", history, 3)
+	requireEqual(t, "initial old_variant_id", history[0].OldVariantID, "")
+	requireEqual(t, "initial new_variant_id", history[0].NewVariantID, pizza.ID)
+	requireEqual(t, "first change old_variant_id", history[1].OldVariantID, pizza.ID)
+	requireEqual(t, "first change new_variant_id", history[1].NewVariantID, sushi.ID)
+	requireTime(t, "first change changed_at", history[1].ChangedAt, at(4))
+	requireEqual(t, "second change user_id", history[2].UserID, bob.ID)
+	requireEqual(t, "second change old_variant_id", history[2].OldVariantID, sushi.ID)
+	requireEqual(t, "second change new_variant_id", history[2].NewVariantID, pizza.ID)
+
+	// Голос за тот же вариант уже есть у этого пользователя
+	other := newVote(t, s, alice.ID, sushi.ID, at(8))
+	_, err = s.PatchVote(ctx, other.ID, ptr(bob.ID), ptr(pizza.ID), at(9))
+	requireError(t, err, apperrors.ErrVoteAlreadyExist)
+
+	// Смена варианта переносит голос в выборы нового варианта
+	dinner := newElection(t, s, alice.ID, "Dinner", "Where to eat", at(1))
+	steak := newVoteVariant(t, s, dinner.ID, "Steak", at(2))
+	_, err = s.PatchVote(ctx, other.ID, nil, ptr(steak.ID), at(9))
+	requireNoError(t, err)
+	again := newVote(t, s, alice.ID, pizza.ID, at(10))
+	_, err = s.PatchVote(ctx, again.ID, nil, ptr(steak.ID), at(11))
+	requireError(t, err, apperrors.ErrVoteAlreadyExist)
+
+	_, err = s.PatchVote(ctx, uuid.NewString(), nil, ptr(pizza.ID), at(9))
+	requireError(t, err, apperrors.ErrVoteNotFound)
+}
+
+func testVoteUserVotes(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(0))
+	lunch := newElection(t, s, alice.ID, "Lunch", "Where to eat", at(1))
+	dinner := newElection(t, s, alice.ID, "Dinner", "Where to eat", at(1))
+	breakfast := newElection(t, s, alice.ID, "Breakfast", "Where to eat", at(1))
+	pizza := newVoteVariant(t, s, lunch.ID, "Pizza", at(2))
+	sushi := newVoteVariant(t, s, dinner.ID, "Sushi", at(2))
+	pasta := newVoteVariant(t, s, breakfast.ID, "Pasta", at(2))
+
+	first := newVote(t, s, alice.ID, pizza.ID, at(3))
+	second := newVote(t, s, alice.ID, sushi.ID, at(4))
+	third := newVote(t, s, alice.ID, pasta.ID, at(5))
+	newVote(t, s, bob.ID, pizza.ID, at(6))
+
+	tests := []struct {
+		name     string
+		variants []string
+		limit    int
+		offset   int
+		want     []string
+	}{
+		{"all", nil, 10, 0, []string{third.ID, second.ID, first.ID}},
+		{"variant filter", []string{pizza.ID, pasta.ID}, 10, 0, []string{third.ID, first.ID}},
+		{"first page", nil, 2, 0, []string{third.ID, second.ID}},
+		{"last page", nil, 2, 2, []string{first.ID}},
+		{"offset past the end", nil, 2, 3, nil},
+		{"zero limit", nil, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		got, err := s.GetUserVotes(ctx, alice.ID, tt.variants, tt.limit, tt.offset)
+		requireNoError(t, err)
+		requireIDs(t, tt.name, ids(got, voteID), tt.want...)
+	}
+
+	got, err := s.GetUserVotes(ctx, uuid.NewString(), nil, 10, 0)
+	requireNoError(t, err)
+	requireLen(t, "unknown user", got, 0)
+}
+
+func testVoteElectionResults(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	alice := newUser(t, s, "alice", at(0))
+	bob := newUser(t, s, "bob", at(0))
+	carol := newUser(t, s, "carol", at(0))
+	dave := newUser(t, s, "dave", at(0))
+	election := newElection(t, s, alice.ID, "Lunch", "Where to eat", at(1))
+	other := newElection(t, s, alice.ID, "Dinner", "Where to eat", at(1))
+	pizza := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	sushi := newVoteVariant(t, s, election.ID, "Sushi", at(2))
+	burger := newVoteVariant(t, s, election.ID, "Burger", at(2))
+	pasta := newVoteVariant(t, s, election.ID, "Pasta", at(2))
+	deleted := newVoteVariant(t, s, election.ID, "Tacos", at(2))
+	steak := newVoteVariant(t, s, other.ID, "Steak", at(2))
+
+	first := newVote(t, s, alice.ID, sushi.ID, at(3))
+	second := newVote(t, s, bob.ID, sushi.ID, at(4))
+	third := newVote(t, s, carol.ID, pizza.ID, at(5))
+	newVote(t, s, dave.ID, deleted.ID, at(6))
+	newVote(t, s, bob.ID, steak.ID, at(7))
+	requireNoError(t, s.DeleteVoteVariant(ctx, deleted.ID, nil, at(8)))
+
+	// Сначала больше голосов, при равенстве по имени, удаленные варианты не учитываются
+	results, err := s.GetElectionResults(ctx, election.ID)
+	requireNoError(t, err)
+	requireLen(t, "results", results, 4)
+	want := []models.VariantResult{
+		{VariantID: sushi.ID, Name: "Sushi", Votes: 2},
+		{VariantID: pizza.ID, Name: "Pizza", Votes: 1},
+		{VariantID: burger.ID, Name: "Burger", Votes: 0},
+		{VariantID: pasta.ID, Name: "Pasta", Votes: 0},
+	}
+	for i := range want {
+		requireEqual(t, "result", *results[i], want[i])
+	}
+
+	// Голоса за удаленные варианты в выгрузку не попадают
+	votes, err := s.GetElectionVotes(ctx, election.ID)
+	requireNoError(t, err)
+	requireIDs(t, "election votes", ids(votes, voteID), first.ID, second.ID, third.ID)
+
+	results, err = s.GetElectionResults(ctx, uuid.NewString())
+	requireNoError(t, err)
+	requireLen(t, "unknown election results", results, 0)
+
+	votes, err = s.GetElectionVotes(ctx, uuid.NewString())
+	requireNoError(t, err)
+	requireLen(t, "unknown election votes", votes, 0)
+}
+
+func testVoteDelete(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	vote := newVote(t, s, user.ID, voteVariant.ID, at(3))
+
+	requireNoError(t, s.DeleteVote(ctx, vote.ID, at(4)))
+	requireError(t, s.DeleteVote(ctx, vote.ID, at(5)), apperrors.ErrVoteNotFound)
+
+	_, err := s.GetVote(ctx, vote.ID)
+	requireError(t, err, apperrors.ErrVoteNotFound)
+
+	// История переживает отзыв голоса и заканчивается записью об отзыве
+	history, err := s.GetVoteHistory(ctx, vote.ID)
+	requireNoError(t, err)
+	requireLen(t, "history", history, 2)
+	requireEqual(t, "new_variant_id", history[0].NewVariantID, voteVariant.ID)
+	requireEqual(t, "withdrawal user_id", history[1].UserID, user.ID)
+	requireEqual(t, "withdrawal old_variant_id", history[1].OldVariantID, voteVariant.ID)
+	requireEqual(t, "withdrawal new_variant_id", history[1].NewVariantID, "")
+	requireTime(t, "withdrawal changed_at", history[1].ChangedAt, at(4))
+
+	// После отзыва можно проголосовать заново
+	newVote(t, s, user.ID, voteVariant.ID, at(6))
+}
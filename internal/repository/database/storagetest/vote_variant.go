@@ -0,0 +1,185 @@
+package storagetest
+
+import (
+	"slices"
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/service"
+	"github.com/google/uuid"
+)
+
+var voteVariantCases = []testCase{
+	{"create and get", testVoteVariantCreateAndGet},
+	{"update", testVoteVariantUpdate},
+	{"delete, restore and purge", testVoteVariantDeleteRestorePurge},
+	{"deleted election", testVoteVariantDeletedElection},
+}
+
+func testVoteVariantCreateAndGet(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	lunch := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	dinner := newElection(t, s, user.ID, "Dinner", "Where to eat", at(1))
+	id := uuid.NewString()
+
+	voteVariant, err := s.CreateVoteVariant(ctx, id, lunch.ID, "Pizza", at(2), at(3))
+	requireNoError(t, err)
+	requireEqual(t, "id", voteVariant.ID, id)
+	requireEqual(t, "election_id", voteVariant.ElectionID, lunch.ID)
+	requireEqual(t, "name", voteVariant.Name, "Pizza")
+	requireTime(t, "created_at", voteVariant.CreatedAt, at(2))
+	requireTime(t, "updated_at", voteVariant.UpdatedAt, at(3))
+	requireEqual(t, "version", voteVariant.Version, 1)
+
+	got, err := s.GetVoteVariant(ctx, id)
+	requireNoError(t, err)
+	requireEqual(t, "name", got.Name, "Pizza")
+
+	_, err = s.GetVoteVariant(ctx, uuid.NewString())
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	_, err = s.CreateVoteVariant(ctx, uuid.NewString(), uuid.NewString(), "Sushi", at(4), at(4))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	// В мягко удаленные выборы варианты не добавляются
+	removed := newElection(t, s, user.ID, "Breakfast", "Where to eat", at(1))
+	requireNoError(t, s.DeleteElection(ctx, removed.ID, nil, at(2)))
+	_, err = s.CreateVoteVariant(ctx, uuid.NewString(), removed.ID, "Sushi", at(4), at(4))
+	requireError(t, err, apperrors.ErrElectionNotFound)
+
+	sushi := newVoteVariant(t, s, lunch.ID, "Sushi", at(4))
+	deleted := newVoteVariant(t, s, lunch.ID, "Burger", at(5))
+	newVoteVariant(t, s, dinner.ID, "Pasta", at(5))
+	requireNoError(t, s.DeleteVoteVariant(ctx, deleted.ID, nil, at(6)))
+
+	// Порядок вариантов не гарантируется
+	variants, err := s.GetVoteVariants(ctx, lunch.ID)
+	requireNoError(t, err)
+	gotIDs := ids(variants, func(v *models.VoteVariant) string { return v.ID })
+	slices.Sort(gotIDs)
+	wantIDs := []string{id, sushi.ID}
+	slices.Sort(wantIDs)
+	requireIDs(t, "election variants", gotIDs, wantIDs...)
+
+	variantIDs, err := s.GetVoteVariantIDs(ctx, lunch.ID)
+	requireNoError(t, err)
+	slices.Sort(variantIDs)
+	requireIDs(t, "election variant ids", variantIDs, wantIDs...)
+
+	variants, err = s.GetVoteVariants(ctx, "")
+	requireNoError(t, err)
+	requireLen(t, "empty election id", variants, 0)
+
+	variants, err = s.GetVoteVariants(ctx, uuid.NewString())
+	requireNoError(t, err)
+	requireLen(t, "unknown election", variants, 0)
+
+	variantIDs, err = s.GetVoteVariantIDs(ctx, uuid.NewString())
+	requireNoError(t, err)
+	requireLen(t, "unknown election ids", variantIDs, 0)
+}
+
+func testVoteVariantUpdate(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+
+	updated, err := s.UpdateVoteVariant(ctx, voteVariant.ID, "Sushi", ptr(int64(1)), at(3))
+	requireNoError(t, err)
+	requireEqual(t, "name", updated.Name, "Sushi")
+	requireEqual(t, "election_id", updated.ElectionID, election.ID)
+	requireEqual(t, "version", updated.Version, 2)
+	requireTime(t, "created_at", updated.CreatedAt, at(2))
+	requireTime(t, "updated_at", updated.UpdatedAt, at(3))
+
+	_, err = s.UpdateVoteVariant(ctx, voteVariant.ID, "Burger", ptr(int64(1)), at(4))
+	requireError(t, err, apperrors.ErrVersionConflict)
+
+	updated, err = s.UpdateVoteVariant(ctx, voteVariant.ID, "Burger", nil, at(4))
+	requireNoError(t, err)
+	requireEqual(t, "version", updated.Version, 3)
+
+	_, err = s.UpdateVoteVariant(ctx, uuid.NewString(), "Pasta", nil, at(5))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+}
+
+func testVoteVariantDeleteRestorePurge(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	vote := newVote(t, s, user.ID, voteVariant.ID, at(3))
+
+	requireError(t, s.DeleteVoteVariant(ctx, voteVariant.ID, ptr(int64(2)), at(4)), apperrors.ErrVersionConflict)
+	requireNoError(t, s.DeleteVoteVariant(ctx, voteVariant.ID, ptr(int64(1)), at(4)))
+	requireError(t, s.DeleteVoteVariant(ctx, voteVariant.ID, nil, at(5)), apperrors.ErrVoteVariantNotFound)
+	requireError(t, s.DeleteVoteVariant(ctx, uuid.NewString(), nil, at(5)), apperrors.ErrVoteVariantNotFound)
+
+	_, err := s.GetVoteVariant(ctx, voteVariant.ID)
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+	_, err = s.UpdateVoteVariant(ctx, voteVariant.ID, "Sushi", nil, at(5))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	restored, err := s.RestoreVoteVariant(ctx, voteVariant.ID, at(6))
+	requireNoError(t, err)
+	requireEqual(t, "version", restored.Version, 3)
+	requireTime(t, "updated_at", restored.UpdatedAt, at(6))
+
+	_, err = s.RestoreVoteVariant(ctx, voteVariant.ID, at(7))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	requireNoError(t, s.DeleteVoteVariant(ctx, voteVariant.ID, nil, at(8)))
+
+	purged, err := s.PurgeVoteVariants(ctx, at(8))
+	requireNoError(t, err)
+	requireEqual(t, "purged before deletion", purged, 0)
+
+	purged, err = s.PurgeVoteVariants(ctx, at(9))
+	requireNoError(t, err)
+	requireEqual(t, "purged", purged, 1)
+
+	_, err = s.RestoreVoteVariant(ctx, voteVariant.ID, at(10))
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	// Голоса за окончательно удаленный вариант удаляются вместе с ним
+	_, err = s.GetVote(ctx, vote.ID)
+	requireError(t, err, apperrors.ErrVoteNotFound)
+}
+
+// Варианты мягко удаленных выборов не читаются, как и итоги по ним
+func testVoteVariantDeletedElection(t *testing.T, s service.Storage) {
+	ctx := t.Context()
+	user := newUser(t, s, "alice", at(0))
+	election := newElection(t, s, user.ID, "Lunch", "Where to eat", at(1))
+	voteVariant := newVoteVariant(t, s, election.ID, "Pizza", at(2))
+	newVote(t, s, user.ID, voteVariant.ID, at(3))
+	requireNoError(t, s.DeleteElection(ctx, election.ID, nil, at(4)))
+
+	_, err := s.GetVoteVariant(ctx, voteVariant.ID)
+	requireError(t, err, apperrors.ErrVoteVariantNotFound)
+
+	variants, err := s.GetVoteVariants(ctx, election.ID)
+	requireNoError(t, err)
+	requireLen(t, "variants", variants, 0)
+
+	variantIDs, err := s.GetVoteVariantIDs(ctx, election.ID)
+	requireNoError(t, err)
+	requireLen(t, "variant ids", variantIDs, 0)
+
+	results, err := s.GetElectionResults(ctx, election.ID)
+	requireNoError(t, err)
+	requireLen(t, "results", results, 0)
+
+	votes, err := s.GetElectionVotes(ctx, election.ID)
+	requireNoError(t, err)
+	requireLen(t, "election votes", votes, 0)
+
+	// После восстановления выборов варианты снова видны
+	_, err = s.RestoreElection(ctx, election.ID, at(5))
+	requireNoError(t, err)
+	_, err = s.GetVoteVariant(ctx, voteVariant.ID)
+	requireNoError(t, err)
+}
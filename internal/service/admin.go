@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s Service) SetUserRole(ctx context.Context, meta models.RequestMeta, userID, role string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetUserRole")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if meta.ActorID() == userID {
+		return nil, apperrors.ErrCannotChangeOwnRole
+	}
+
+	user, err := s.UserService.userRepository.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.AuthService.roleRepository.GetUserRole(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.AuthService.roleRepository.SetUserRole(ctx, user.ID, role, time.Now()); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	s.AuditService.record(ctx, meta, models.AuditActionSetRole, models.AuditEntityUser, user.ID,
+		map[string]string{"role": before}, map[string]string{"role": role})
+
+	return user, nil
+}
+
+func (s Service) CloseElection(ctx context.Context, meta models.RequestMeta, electionID string) (*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "Service.CloseElection")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	before, err := s.ElectionService.electionRepository.GetElection(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.ElectionStatusClosed
+	election, err := s.ElectionService.electionRepository.PatchElection(ctx, electionID, nil, nil, nil, &status, nil, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionClose, models.AuditEntityElection, election.ID, before, election)
+	countElectionStatus(before.Status, election.Status)
+
+	return election, nil
+}
+
+func (s Service) RemoveVote(ctx context.Context, meta models.RequestMeta, voteID string) error {
+	ctx, span := tracer.Start(ctx, "Service.RemoveVote")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	// Модератор удаляет голос в обход политики выборов
+	before, err := s.VoteService.voteRepository.GetVote(ctx, voteID)
+	if err != nil {
+		return err
+	}
+
+	err = s.VoteService.voteRepository.DeleteVote(ctx, voteID, time.Now())
+	if err != nil {
+		return err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionDelete, models.AuditEntityVote, voteID, before, nil)
+
+	return nil
+}
+
+func (s Service) RestoreUser(ctx context.Context, meta models.RequestMeta, userID string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "Service.RestoreUser")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserService.userRepository.RestoreUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionRestore, models.AuditEntityUser, user.ID, nil, user)
+
+	return user, nil
+}
+
+func (s Service) RestoreElection(ctx context.Context, meta models.RequestMeta, electionID string) (*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "Service.RestoreElection")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	election, err := s.ElectionService.electionRepository.RestoreElection(ctx, electionID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionRestore, models.AuditEntityElection, election.ID, nil, election)
+
+	return election, nil
+}
+
+func (s Service) RestoreVoteVariant(ctx context.Context, meta models.RequestMeta, voteVariantID string) (*models.VoteVariant, error) {
+	ctx, span := tracer.Start(ctx, "Service.RestoreVoteVariant")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	voteVariant, err := s.VoteVariantService.voteVariantRepository.RestoreVoteVariant(ctx, voteVariantID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionRestore, models.AuditEntityVoteVariant, voteVariant.ID, nil, voteVariant)
+
+	return voteVariant, nil
+}
+
+func (s Service) PurgeDeleted(ctx context.Context, meta models.RequestMeta) (*models.PurgeResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.PurgeDeleted")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return s.purgeDeleted(ctx, meta)
+}
+
+// Окончательно удаляет записи старше retention по расписанию, в журнале без actor
+func (s Service) PurgeExpiredDeleted(ctx context.Context) (*models.PurgeResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.PurgeExpiredDeleted")
+	defer span.End()
+
+	return s.purgeDeleted(ctx, models.RequestMeta{})
+}
+
+func (s Service) purgeDeleted(ctx context.Context, meta models.RequestMeta) (*models.PurgeResult, error) {
+	deletedBefore := time.Now().Add(-s.softDeleteCfg.Retention)
+
+	// Сначала дочерние записи, иначе каскад родителя посчитает их за нас
+	voteVariants, err := s.VoteVariantService.voteVariantRepository.PurgeVoteVariants(ctx, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	elections, err := s.ElectionService.electionRepository.PurgeElections(ctx, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.UserService.userRepository.PurgeUsers(ctx, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PurgeResult{
+		Users:        users,
+		Elections:    elections,
+		VoteVariants: voteVariants,
+	}
+	// Одна запись на весь проход: отдельные строки уже удалены вместе со снимками
+	s.AuditService.record(ctx, meta, models.AuditActionPurge, models.AuditEntityPurge, deletedBefore.Format(time.RFC3339), nil, result)
+
+	return result, nil
+}
+
+// Бюллетени раскрывают, кто за что голосовал, поэтому только модератору и админу
+func (s Service) ExportBallots(ctx context.Context, actor *models.User, electionID string) ([]*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "Service.ExportBallots")
+	defer span.End()
+
+	if err := requireRole(actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ElectionService.electionRepository.GetElection(ctx, electionID); err != nil {
+		return nil, err
+	}
+
+	return s.VoteService.voteRepository.GetElectionVotes(ctx, electionID)
+}
+
+// Сброс без текущего пароля, заодно снимается блокировка входа по nickname
+func (s Service) ResetPassword(ctx context.Context, meta models.RequestMeta, userID, newPassword string) error {
+	ctx, span := tracer.Start(ctx, "Service.ResetPassword")
+	defer span.End()
+
+	if err := requireRole(meta.Actor, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	user, err := s.UserService.userRepository.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.ErrFailedToHashPassword
+	}
+
+	if err := s.UserService.userRepository.UpdateUserPassword(ctx, user.ID, string(hashedPassword), time.Now()); err != nil {
+		return err
+	}
+	if err := s.AuthService.loginAttemptRepository.ResetLoginAttempts(ctx, nicknameKey(user.Nickname)); err != nil {
+		return err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionResetPassword, models.AuditEntityUser, user.ID, nil, nil)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/google/uuid"
+)
+
+// Снимок пользователя для журнала. Поля перечислены явно: встроенный *models.User
+// вместе с тегом json:"-" не скрыл бы его Password
+type auditUser struct {
+	ID        string
+	Nickname  string
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int64
+}
+
+func (s AuditService) GetAuditEvents(ctx context.Context, actor *models.User, limit, offset int, filter models.AuditFilter) ([]*models.AuditEvent, error) {
+	ctx, span := tracer.Start(ctx, "AuditService.GetAuditEvents")
+	defer span.End()
+
+	if err := requireRole(actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	validLimit := validateLimit(limit)
+	validOffset := validateOffset(offset)
+
+	events, err := s.auditRepository.GetAuditEvents(ctx, validLimit, validOffset, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Запись в журнал не откатывает уже выполненное изменение, ошибка только логируется
+func (s AuditService) record(ctx context.Context, meta models.RequestMeta, action, entityType, entityID string, before, after any) {
+	event := &models.AuditEvent{
+		ID:         uuid.New().String(),
+		ActorID:    meta.ActorID(),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+		RequestID:  meta.RequestID,
+		CreatedAt:  time.Now(),
+	}
+
+	// Изменение уже выполнено, поэтому отмена запроса клиентом не должна терять запись
+	if err := s.auditRepository.CreateAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "Failed to record audit event",
+			"action", action,
+			"entity_type", entityType,
+			"entity_id", entityID,
+			"error", err)
+	}
+}
+
+func auditSnapshot(entity any) []byte {
+	switch v := entity.(type) {
+	case nil:
+		return nil
+	case *models.User:
+		if v == nil {
+			return nil
+		}
+		entity = auditUser{
+			ID:        v.ID,
+			Nickname:  v.Nickname,
+			Role:      v.Role,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+			Version:   v.Version,
+		}
+	}
+
+	data, err := json.Marshal(entity)
+	if err != nil {
+		slog.Error("Failed to marshal audit snapshot", "error", err)
+		return nil
+	}
+
+	return data
+}
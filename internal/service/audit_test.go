@@ -0,0 +1,44 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func TestAuditSnapshotHidesPassword(t *testing.T) {
+	user := &models.User{
+		ID:        "6f1c2b4e-0000-4000-8000-000000000001",
+		Nickname:  "alice",
+		Password:  "$2a$10$hash",
+		Role:      models.RoleUser,
+		CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+
+	data := auditSnapshot(user)
+
+	var snapshot map[string]any
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("unmarshal snapshot: %v", err)
+	}
+	for key := range snapshot {
+		if strings.EqualFold(key, "password") {
+			t.Fatalf("snapshot exposes %q: %s", key, data)
+		}
+	}
+	if strings.Contains(string(data), user.Password) {
+		t.Fatalf("snapshot contains password hash: %s", data)
+	}
+	if snapshot["ID"] != user.ID || snapshot["Nickname"] != user.Nickname {
+		t.Fatalf("snapshot lost user fields: %s", data)
+	}
+
+	if auditSnapshot((*models.User)(nil)) != nil {
+		t.Fatal("nil user must give empty snapshot")
+	}
+}
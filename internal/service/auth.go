@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/alonsoF100/golos/internal/metrics"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Хэш с той же стоимостью, что и у настоящих паролей: неизвестный nickname
+// проверяется так же долго, как и существующий
+const dummyPasswordHash = "$2a$10$7vbGRfh6hJsqr9vMk56nK.QbDkyH9/50r9nshDBcgB7VIx4XQrthi"
+
+func (s AuthService) Authenticate(ctx context.Context, nickname, password, ip string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Authenticate")
+	defer span.End()
+
+	if err := s.checkLoginAllowed(ctx, nickname, ip); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.GetUserByNickname(ctx, nickname)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrUserNotFound) {
+			// Неизвестный nickname считается такой же ошибкой, иначе перебор раскрыл бы существующих пользователей
+			_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+			return nil, s.registerLoginFailure(ctx, nickname, ip, apperrors.ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, s.registerLoginFailure(ctx, nickname, ip, apperrors.ErrInvalidCredentials)
+	}
+
+	if err := s.loginAttemptRepository.ResetLoginAttempts(ctx, nicknameKey(nickname)); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepository.GetUserRole(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	return user, nil
+}
+
+func (s AuthService) ChangePassword(ctx context.Context, meta models.RequestMeta, uuid, currentPassword, newPassword, ip string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
+	now := time.Now()
+
+	user, err := s.userRepository.GetUser(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkLoginAllowed(ctx, user.Nickname, ip); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return s.registerLoginFailure(ctx, user.Nickname, ip, apperrors.ErrWrongPassword)
+	}
+
+	if err := s.loginAttemptRepository.ResetLoginAttempts(ctx, nicknameKey(user.Nickname)); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.ErrFailedToHashPassword
+	}
+
+	err = s.userRepository.UpdateUserPassword(ctx, uuid, string(hashedPassword), now)
+	if err != nil {
+		return err
+	}
+	// Снимок пользователя (auditUser) хэш не содержит, поэтому фиксируется только сам факт смены
+	s.audit.record(ctx, meta, models.AuditActionChangePassword, models.AuditEntityUser, uuid, nil, nil)
+
+	return nil
+}
+
+func nicknameKey(nickname string) string {
+	return "nickname:" + nickname
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Блокировка по nickname защищает аккаунт (423), по ip - от перебора многих аккаунтов (429)
+// Роль пользователя для self view, которую видят модераторы и администраторы
+func (s AuthService) GetUserRole(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GetUserRole")
+	defer span.End()
+
+	return s.roleRepository.GetUserRole(ctx, userID)
+}
+
+func (s AuthService) checkLoginAllowed(ctx context.Context, nickname, ip string) error {
+	now := time.Now()
+
+	if s.cfg.MaxFailedAttempts > 0 {
+		attempt, err := s.loginAttemptRepository.GetLoginAttempt(ctx, nicknameKey(nickname))
+		if err != nil {
+			return err
+		}
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			return authFailure(apperrors.ErrAccountLocked)
+		}
+	}
+
+	if s.cfg.MaxFailedAttemptsPerIP > 0 && ip != "" {
+		attempt, err := s.loginAttemptRepository.GetLoginAttempt(ctx, ipKey(ip))
+		if err != nil {
+			return err
+		}
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			return authFailure(apperrors.ErrTooManyLoginAttempts)
+		}
+	}
+
+	return nil
+}
+
+// Возвращает failure, если запись счетчиков прошла успешно
+func (s AuthService) registerLoginFailure(ctx context.Context, nickname, ip string, failure *apperrors.AppError) error {
+	if s.cfg.MaxFailedAttempts > 0 {
+		if err := s.registerKeyFailure(ctx, nicknameKey(nickname), s.cfg.MaxFailedAttempts); err != nil {
+			return err
+		}
+	}
+	if s.cfg.MaxFailedAttemptsPerIP > 0 && ip != "" {
+		if err := s.registerKeyFailure(ctx, ipKey(ip), s.cfg.MaxFailedAttemptsPerIP); err != nil {
+			return err
+		}
+	}
+
+	return authFailure(failure)
+}
+
+// Причина в метрике - код ошибки, сбои хранилища не считаются
+func authFailure(failure *apperrors.AppError) error {
+	metrics.AuthFailures.WithLabelValues(failure.Code).Inc()
+	return failure
+}
+
+func (s AuthService) registerKeyFailure(ctx context.Context, key string, threshold int) error {
+	now := time.Now()
+
+	attempt, err := s.loginAttemptRepository.RegisterLoginFailure(ctx, key, now, s.cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures < threshold {
+		return nil
+	}
+
+	duration := s.lockoutDuration(attempt.Failures - threshold)
+	if duration <= 0 {
+		return nil
+	}
+
+	lockedUntil := now.Add(duration)
+	if err := s.loginAttemptRepository.SetLoginLock(ctx, key, lockedUntil); err != nil {
+		return err
+	}
+
+	_, err = s.loginLockoutRepository.CreateLoginLockout(ctx, uuid.New().String(), key, attempt.Failures, lockedUntil, now)
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "Login locked", "key", key, "failures", attempt.Failures, "locked_until", lockedUntil)
+
+	return nil
+}
+
+// Экспоненциальный рост: base, 2*base, 4*base ... но не больше max_lockout
+func (s AuthService) lockoutDuration(exceeded int) time.Duration {
+	duration := s.cfg.BaseLockout
+	for range exceeded {
+		if duration >= s.cfg.MaxLockout {
+			break
+		}
+		duration *= 2
+	}
+
+	return min(duration, s.cfg.MaxLockout)
+}
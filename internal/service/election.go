@@ -1,53 +1,138 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/metrics"
 	"github.com/alonsoF100/golos/internal/models"
 	"github.com/google/uuid"
 )
 
-func (s ElectionService) CreateElection(userID string, name string, description string) (*models.Election, error) {
+func (s ElectionService) CreateElection(ctx context.Context, meta models.RequestMeta, userID string, name string, description string, votePolicy string) (*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.CreateElection")
+	defer span.End()
+
+	// Обычный пользователь создает выборы только от своего имени
+	if err := requireOwner(meta.Actor, userID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	id := uuid.New().String()
+	if votePolicy == "" {
+		votePolicy = models.VotePolicyChangesUntilClose
+	}
 
-	election, err := s.electionRepository.CreateElection(id, userID, name, description, now, now)
+	election, err := s.electionRepository.CreateElection(ctx, id, userID, name, description, votePolicy, now, now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionCreate, models.AuditEntityElection, election.ID, nil, election)
+	countElectionStatus("", election.Status)
 
 	return election, nil
 }
 
-func (s ElectionService) GetElection(uuid string) (*models.Election, error) {
-	election, err := s.electionRepository.GetElection(uuid)
+func (s ElectionService) GetElection(ctx context.Context, uuid, locale string) (*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.GetElection")
+	defer span.End()
+
+	election, err := s.electionRepository.GetElection(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.localizeElections(ctx, locale, election); err != nil {
+		return nil, err
+	}
+
 	return election, nil
 }
 
-func (s ElectionService) DeleteElection(uuid string) error {
-	err := s.electionRepository.DeleteElection(uuid)
+func (s ElectionService) DeleteElection(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error {
+	ctx, span := tracer.Start(ctx, "ElectionService.DeleteElection")
+	defer span.End()
+
+	before, err := s.electionRepository.GetElection(ctx, uuid)
 	if err != nil {
 		return err
 	}
+	if err := requireOwner(meta.Actor, before.UserID); err != nil {
+		return err
+	}
+
+	err = s.electionRepository.DeleteElection(ctx, uuid, matchVersion(versions, before.Version), time.Now())
+	if err != nil {
+		return err
+	}
+	s.audit.record(ctx, meta, models.AuditActionDelete, models.AuditEntityElection, uuid, before, nil)
 
 	return nil
 }
 
-func (s ElectionService) PatchElection(uuid string, userID, name, description *string) (*models.Election, error) {
+func (s ElectionService) PatchElection(ctx context.Context, meta models.RequestMeta, uuid string, userID, name, description, status, votePolicy *string, versions []int64) (*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.PatchElection")
+	defer span.End()
+
 	now := time.Now()
-	if userID == nil && name == nil && description == nil {
+	if userID == nil && name == nil && description == nil && status == nil && votePolicy == nil {
 		return nil, apperrors.ErrNothingToChange
 	}
 
-	election, err := s.electionRepository.PatchElection(uuid, userID, name, description, now)
+	before, err := s.electionRepository.GetElection(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
+	if err := requireOwner(meta.Actor, before.UserID); err != nil {
+		return nil, err
+	}
+	// Передать выборы другому пользователю может только модератор или администратор
+	if userID != nil && *userID != before.UserID {
+		if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	election, err := s.electionRepository.PatchElection(ctx, uuid, userID, name, description, status, votePolicy, matchVersion(versions, before.Version), now)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.record(ctx, meta, models.AuditActionPatch, models.AuditEntityElection, election.ID, before, election)
+	countElectionStatus(before.Status, election.Status)
 
 	return election, nil
 }
+
+func (s ElectionService) SearchElections(ctx context.Context, limit, offset int, filter models.ElectionFilter, locale string) ([]*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.SearchElections")
+	defer span.End()
+
+	validLimit := validateLimit(limit)
+	validOffset := validateOffset(offset)
+
+	elections, err := s.electionRepository.GetElections(ctx, validLimit, validOffset, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.localizeElections(ctx, locale, elections...); err != nil {
+		return nil, err
+	}
+
+	return elections, nil
+}
+
+// Учитывает только переходы статуса, повторный patch с тем же статусом не считается
+func countElectionStatus(before, after string) {
+	if before == after {
+		return
+	}
+	switch after {
+	case models.ElectionStatusOpen:
+		metrics.ElectionsOpened.Inc()
+	case models.ElectionStatusClosed:
+		metrics.ElectionsClosed.Inc()
+	}
+}
@@ -1,47 +1,58 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/metrics"
 	"github.com/alonsoF100/golos/internal/models"
+	"github.com/google/uuid"
 )
 
-func (s Service) GetElections(limit, offset int, nickname string) ([]*models.Election, error) {
+func (s Service) GetElections(ctx context.Context, limit, offset int, nickname string, filter models.ElectionFilter, locale string) ([]*models.Election, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetElections")
+	defer span.End()
+
 	validateLimit := validateLimit(limit)
 	validateOffset := validateOffset(offset)
 
-	user, err := s.UserService.userRepository.GetUserByNickname(nickname)
+	user, err := s.UserService.userRepository.GetUserByNickname(ctx, nickname)
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
 
-	elections, err := s.ElectionService.electionRepository.GetElections(validateLimit, validateOffset, user.ID)
+	filter.UserID = user.ID
+	elections, err := s.ElectionService.electionRepository.GetElections(ctx, validateLimit, validateOffset, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.ElectionService.localizeElections(ctx, locale, elections...); err != nil {
+		return nil, err
+	}
+
 	return elections, nil
 }
 
-func (s Service) GetUserVotes(nickname, electionID string, limit int, offset int) ([]*models.Vote, error) {
+func (s Service) GetUserVotes(ctx context.Context, nickname, electionID string, limit int, offset int) ([]*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUserVotes")
+	defer span.End()
+
 	validLimit := validateLimit(limit)
 	validOffset := validateOffset(offset)
 
-	user, err := s.UserService.userRepository.GetUserByNickname(nickname)
+	user, err := s.UserService.userRepository.GetUserByNickname(ctx, nickname)
 	if err != nil {
 		return nil, apperrors.ErrUserNotFound
 	}
 
-	voteVariants, err := s.VoteVariantService.voteVariantRepository.GetVoteVariants(electionID)
+	voteVariantIDs, err := s.VoteVariantService.voteVariantRepository.GetVoteVariantIDs(ctx, electionID)
 	if err != nil {
 		return nil, err
 	}
 
-	voteVariantIDs := make([]string, 0, len(voteVariants))
-	for _, voteVariant := range voteVariants {
-		voteVariantIDs = append(voteVariantIDs, voteVariant.ID)
-	}
-
-	votes, err := s.VoteService.voteRepository.GetUserVotes(user.ID, voteVariantIDs, validLimit, validOffset)
+	votes, err := s.VoteService.voteRepository.GetUserVotes(ctx, user.ID, voteVariantIDs, validLimit, validOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -49,6 +60,185 @@ func (s Service) GetUserVotes(nickname, electionID string, limit int, offset int
 	return votes, nil
 }
 
-func (s Service) GetVariantVotes(voteVariantID string) ([]*models.Vote, error) {
+func (s Service) GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetVariantVotes")
+	defer span.End()
+
 	return nil, nil
 }
+
+func (s Service) GetElectionResults(ctx context.Context, electionID string) ([]*models.VariantResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetElectionResults")
+	defer span.End()
+
+	if _, err := s.ElectionService.electionRepository.GetElection(ctx, electionID); err != nil {
+		return nil, err
+	}
+
+	return s.VoteService.voteRepository.GetElectionResults(ctx, electionID)
+}
+
+// Голос принимается только в открытых выборах и только один на выборы:
+// сменить его можно через PatchVote, если это разрешает политика выборов
+func (s Service) CreateVote(ctx context.Context, meta models.RequestMeta, userID, voteVariantID string) (*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateVote")
+	defer span.End()
+
+	// Голосовать можно только от своего имени
+	if err := requireOwner(meta.Actor, userID); err != nil {
+		return nil, err
+	}
+
+	election, err := s.voteElection(ctx, voteVariantID)
+	if err != nil {
+		return nil, err
+	}
+	if election.Status == models.ElectionStatusClosed {
+		return nil, apperrors.ErrElectionClosed
+	}
+
+	voted, err := s.hasElectionVote(ctx, userID, election.ID)
+	if err != nil {
+		return nil, err
+	}
+	if voted {
+		return nil, apperrors.ErrVoteAlreadyExist
+	}
+
+	id := uuid.New().String()
+	now := time.Now()
+
+	// Уникальный индекс (user_id, election_id) закрывает гонку двух одновременных голосов
+	vote, err := s.VoteService.voteRepository.CreateVote(ctx, id, userID, voteVariantID, now, now)
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionCreate, models.AuditEntityVote, vote.ID, nil, vote)
+	metrics.VotesCast.Inc()
+
+	return vote, nil
+}
+
+func (s Service) PatchVote(ctx context.Context, meta models.RequestMeta, voteID string, userID, voteVariantID *string) (*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "Service.PatchVote")
+	defer span.End()
+
+	now := time.Now()
+
+	if userID == nil && voteVariantID == nil {
+		return nil, apperrors.ErrNothingToChange
+	}
+
+	before, err := s.VoteService.voteRepository.GetVote(ctx, voteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOwner(meta.Actor, before.UserID); err != nil {
+		return nil, err
+	}
+	// Передать голос другому пользователю может только модератор или администратор
+	if userID != nil && *userID != before.UserID {
+		if err := requireRole(meta.Actor, models.RoleModerator, models.RoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	election, err := s.voteElection(ctx, before.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVoteChangeAllowed(election); err != nil {
+		return nil, err
+	}
+
+	// Переголосовать можно только за вариант тех же выборов
+	if voteVariantID != nil && *voteVariantID != before.VariantID {
+		variant, err := s.VoteVariantService.voteVariantRepository.GetVoteVariant(ctx, *voteVariantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ElectionID != election.ID {
+			return nil, apperrors.ErrVoteWrongElection
+		}
+	}
+
+	vote, err := s.VoteService.voteRepository.PatchVote(ctx, voteID, userID, voteVariantID, now)
+	if err != nil {
+		return nil, err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionPatch, models.AuditEntityVote, vote.ID, before, vote)
+
+	return vote, nil
+}
+
+// Отзыв голоса - тоже изменение, поэтому подчиняется политике выборов
+func (s Service) DeleteVote(ctx context.Context, meta models.RequestMeta, voteID string) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteVote")
+	defer span.End()
+
+	before, err := s.VoteService.voteRepository.GetVote(ctx, voteID)
+	if err != nil {
+		return err
+	}
+	if err := requireOwner(meta.Actor, before.UserID); err != nil {
+		return err
+	}
+
+	election, err := s.voteElection(ctx, before.VariantID)
+	if err != nil {
+		return err
+	}
+	if err := checkVoteChangeAllowed(election); err != nil {
+		return err
+	}
+
+	err = s.VoteService.voteRepository.DeleteVote(ctx, voteID, time.Now())
+	if err != nil {
+		return err
+	}
+	s.AuditService.record(ctx, meta, models.AuditActionDelete, models.AuditEntityVote, voteID, before, nil)
+
+	return nil
+}
+
+func (s Service) voteElection(ctx context.Context, voteVariantID string) (*models.Election, error) {
+	variant, err := s.VoteVariantService.voteVariantRepository.GetVoteVariant(ctx, voteVariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	election, err := s.ElectionService.electionRepository.GetElection(ctx, variant.ElectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return election, nil
+}
+
+func (s Service) hasElectionVote(ctx context.Context, userID, electionID string) (bool, error) {
+	voteVariantIDs, err := s.VoteVariantService.voteVariantRepository.GetVoteVariantIDs(ctx, electionID)
+	if err != nil {
+		return false, err
+	}
+	if len(voteVariantIDs) == 0 {
+		return false, nil
+	}
+
+	votes, err := s.VoteService.voteRepository.GetUserVotes(ctx, userID, voteVariantIDs, 1, 0)
+	if err != nil {
+		return false, err
+	}
+
+	return len(votes) > 0, nil
+}
+
+func checkVoteChangeAllowed(election *models.Election) error {
+	if election.Status == models.ElectionStatusClosed {
+		return apperrors.ErrElectionClosed
+	}
+	if election.VotePolicy == models.VotePolicyFinal {
+		return apperrors.ErrVoteIsFinal
+	}
+
+	return nil
+}
@@ -1,10 +1,21 @@
 package service
 
+import (
+	"slices"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"go.opentelemetry.io/otel"
+)
+
 const (
 	maxLimit     = 100
 	defaultLimit = 20
 )
 
+// Спан на каждый публичный метод сервиса, без Setup трассировки это noop
+var tracer = otel.Tracer("github.com/alonsoF100/golos/internal/service")
+
 func validateLimit(limit int) int {
 	if limit < 0 {
 		return defaultLimit
@@ -22,3 +33,40 @@ func validateOffset(offset int) int {
 
 	return offset
 }
+
+// Проверка роли выполняется в сервисе, middleware только аутентифицирует
+func requireRole(actor *models.User, roles ...string) error {
+	if actor == nil {
+		return apperrors.ErrUnauthorized
+	}
+	if !slices.Contains(roles, actor.Role) {
+		return apperrors.ErrForbidden
+	}
+
+	return nil
+}
+
+// Менять ресурс может его владелец, модератор или администратор
+func requireOwner(actor *models.User, ownerID string) error {
+	if actor == nil {
+		return apperrors.ErrUnauthorized
+	}
+	if actor.ID == ownerID {
+		return nil
+	}
+
+	return requireRole(actor, models.RoleModerator, models.RoleAdmin)
+}
+
+// Версия для оптимистичной блокировки по списку из If-Match, nil - изменение без проверки.
+// Если текущей версии нет в списке, передается первая из него, и репозиторий вернет конфликт
+func matchVersion(versions []int64, current int64) *int64 {
+	if len(versions) == 0 {
+		return nil
+	}
+	if slices.Contains(versions, current) {
+		return &current
+	}
+
+	return &versions[0]
+}
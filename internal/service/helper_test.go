@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []int64
+		want     *int64
+	}{
+		{name: "any", versions: nil, want: nil},
+		{name: "current in list", versions: []int64{3, 5}, want: ptr(int64(5))},
+		{name: "stale list", versions: []int64{3, 4}, want: ptr(int64(3))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchVersion(tt.versions, 5)
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+// nil без ошибки - ключ занят этим запросом и его нужно выполнить,
+// иначе возвращается сохраненный ответ для повтора
+func (s IdempotencyService) BeginIdempotentRequest(ctx context.Context, key, requestHash string) (*models.IdempotencyRecord, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.BeginIdempotentRequest")
+	defer span.End()
+
+	// Вторая попытка нужна, если ключ удалили между claim и чтением (5xx у первого запроса)
+	for range 2 {
+		now := time.Now()
+
+		claimed, err := s.idempotencyRepository.ClaimIdempotencyKey(ctx, key, requestHash, now, now.Add(s.cfg.TTL))
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		record, err := s.idempotencyRepository.GetIdempotencyRecord(ctx, key)
+		if err != nil {
+			if errors.Is(err, apperrors.ErrIdempotencyKeyNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if record.RequestHash != requestHash {
+			return nil, apperrors.ErrIdempotencyKeyReused
+		}
+		if record.StatusCode == 0 {
+			return nil, apperrors.ErrIdempotencyKeyInProgress
+		}
+
+		return record, nil
+	}
+
+	return nil, apperrors.ErrIdempotencyKeyInProgress
+}
+
+func (s IdempotencyService) CompleteIdempotentRequest(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.CompleteIdempotentRequest")
+	defer span.End()
+
+	return s.idempotencyRepository.SaveIdempotencyResponse(ctx, key, statusCode, etag, location, body)
+}
+
+// Освобождает ключ без сохранения ответа, чтобы клиент мог повторить запрос с тем же ключом
+func (s IdempotencyService) ReleaseIdempotentRequest(ctx context.Context, key string) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.ReleaseIdempotentRequest")
+	defer span.End()
+
+	return s.idempotencyRepository.DeleteIdempotencyKey(ctx, key)
+}
+
+func (s IdempotencyService) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.PurgeExpiredIdempotencyKeys")
+	defer span.End()
+
+	purged, err := s.idempotencyRepository.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
@@ -1,74 +1,161 @@
 package service
 
 import (
+	"context"
 	"time"
 
+	"github.com/alonsoF100/golos/internal/config"
 	"github.com/alonsoF100/golos/internal/models"
-	"github.com/alonsoF100/golos/internal/repository/database/postgres"
 )
 
 type UserRepository interface {
-	CreateUser(id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error)
-	GetUsers(limit, offset int) ([]*models.User, error)
-	GetUser(id string) (*models.User, error)
-	GetUserByNickname(nickname string) (*models.User, error)
-	UpdateUser(id, nickname, password string, updatedAt time.Time) (*models.User, error)
-	DeleteUser(id string) error
-	PatchUser(id string, nickname, password *string, updatedAt time.Time) (*models.User, error)
+	CreateUser(ctx context.Context, id, nickname, password string, createdAt time.Time, updatedAt time.Time) (*models.User, error)
+	GetUsers(ctx context.Context, limit, offset int) ([]*models.User, error)
+	GetUser(ctx context.Context, id string) (*models.User, error)
+	GetUserByNickname(ctx context.Context, nickname string) (*models.User, error)
+	UpdateUser(ctx context.Context, id, nickname string, version *int64, updatedAt time.Time) (*models.User, error)
+	UpdateUserPassword(ctx context.Context, id, password string, updatedAt time.Time) error
+	DeleteUser(ctx context.Context, id string, version *int64, deletedAt time.Time) error
+	RestoreUser(ctx context.Context, id string, updatedAt time.Time) (*models.User, error)
+	PurgeUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PatchUser(ctx context.Context, id string, nickname *string, version *int64, updatedAt time.Time) (*models.User, error)
 }
 
 type ElectionRepository interface {
-	CreateElection(id, userID, name string, description string, createdAt time.Time, updatedAt time.Time) (*models.Election, error)
-	GetElections(limit, offset int, userID string) ([]*models.Election, error)
-	GetElection(id string) (*models.Election, error)
-	DeleteElection(id string) error
-	PatchElection(id string, userID, name, description *string, updatedAt time.Time) (*models.Election, error)
+	CreateElection(ctx context.Context, id, userID, name string, description string, votePolicy string, createdAt time.Time, updatedAt time.Time) (*models.Election, error)
+	GetElections(ctx context.Context, limit, offset int, filter models.ElectionFilter) ([]*models.Election, error)
+	GetElection(ctx context.Context, id string) (*models.Election, error)
+	DeleteElection(ctx context.Context, id string, version *int64, deletedAt time.Time) error
+	RestoreElection(ctx context.Context, id string, updatedAt time.Time) (*models.Election, error)
+	PurgeElections(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PatchElection(ctx context.Context, id string, userID, name, description, status, votePolicy *string, version *int64, updatedAt time.Time) (*models.Election, error)
 }
 
 type VoteVariantRepository interface {
-	CreateVoteVariant(id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error)
-	GetVoteVariants(electionID string) ([]*models.VoteVariant, error)
-	GetVoteVariant(id string) (*models.VoteVariant, error)
-	DeleteVoteVariant(id string) error
-	UpdateVoteVariant(id, name string, updatedAt time.Time) (*models.VoteVariant, error)
+	CreateVoteVariant(ctx context.Context, id, electionID, name string, createdAt time.Time, updatedAt time.Time) (*models.VoteVariant, error)
+	GetVoteVariants(ctx context.Context, electionID string) ([]*models.VoteVariant, error)
+	GetVoteVariantIDs(ctx context.Context, electionID string) ([]string, error)
+	GetVoteVariant(ctx context.Context, id string) (*models.VoteVariant, error)
+	DeleteVoteVariant(ctx context.Context, id string, version *int64, deletedAt time.Time) error
+	RestoreVoteVariant(ctx context.Context, id string, updatedAt time.Time) (*models.VoteVariant, error)
+	PurgeVoteVariants(ctx context.Context, deletedBefore time.Time) (int64, error)
+	UpdateVoteVariant(ctx context.Context, id, name string, version *int64, updatedAt time.Time) (*models.VoteVariant, error)
 }
 
 type VoteRepository interface {
-	CreateVote(uuid, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error)
-	GetVote(uuid string) (*models.Vote, error)
-	GetUserVotes(userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error)
-	GetVariantVotes(voteVariantID string) ([]*models.Vote, error)
-	DeleteVote(uuid string) error
-	PatchVote(uuid string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error)
+	CreateVote(ctx context.Context, uuid, userID, voteVariantID string, createdAt time.Time, updatedAt time.Time) (*models.Vote, error)
+	GetVote(ctx context.Context, uuid string) (*models.Vote, error)
+	GetUserVotes(ctx context.Context, userID string, voteVariantsIDs []string, limit, offset int) ([]*models.Vote, error)
+	GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error)
+	DeleteVote(ctx context.Context, uuid string, deletedAt time.Time) error
+	PatchVote(ctx context.Context, uuid string, userID, voteVariantID *string, updatedAt time.Time) (*models.Vote, error)
+	GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error)
+	GetElectionResults(ctx context.Context, electionID string) ([]*models.VariantResult, error)
+	GetElectionVotes(ctx context.Context, electionID string) ([]*models.Vote, error)
+}
+
+type TranslationRepository interface {
+	SetElectionTranslation(ctx context.Context, electionID, locale, name, description string, updatedAt time.Time) (*models.ElectionTranslation, error)
+	DeleteElectionTranslation(ctx context.Context, electionID, locale string) error
+	GetElectionTranslations(ctx context.Context, electionIDs []string, locale string) ([]*models.ElectionTranslation, error)
+	SetVoteVariantTranslation(ctx context.Context, voteVariantID, locale, name string, updatedAt time.Time) (*models.VoteVariantTranslation, error)
+	DeleteVoteVariantTranslation(ctx context.Context, voteVariantID, locale string) error
+	GetVoteVariantTranslations(ctx context.Context, voteVariantIDs []string, locale string) ([]*models.VoteVariantTranslation, error)
+}
+
+type RoleRepository interface {
+	GetUserRole(ctx context.Context, userID string) (string, error)
+	SetUserRole(ctx context.Context, userID, role string, updatedAt time.Time) error
+}
+
+type LoginAttemptRepository interface {
+	RegisterLoginFailure(ctx context.Context, key string, failedAt time.Time, window time.Duration) (*models.LoginAttempt, error)
+	SetLoginLock(ctx context.Context, key string, lockedUntil time.Time) error
+	GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error)
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
+
+type LoginLockoutRepository interface {
+	CreateLoginLockout(ctx context.Context, id, key string, failures int, lockedUntil time.Time, createdAt time.Time) (*models.LoginLockout, error)
+}
+
+type IdempotencyRepository interface {
+	ClaimIdempotencyKey(ctx context.Context, key, requestHash string, createdAt time.Time, expiresAt time.Time) (bool, error)
+	GetIdempotencyRecord(ctx context.Context, key string) (*models.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) error
+	GetAuditEvents(ctx context.Context, limit, offset int, filter models.AuditFilter) ([]*models.AuditEvent, error)
+}
+
+// Все репозитории одного хранилища: postgres или memory.
+// Счетчики входов могут жить отдельно, в redis
+type Storage interface {
+	UserRepository
+	ElectionRepository
+	VoteVariantRepository
+	VoteRepository
+	TranslationRepository
+	RoleRepository
+	LoginAttemptRepository
+	LoginLockoutRepository
+	IdempotencyRepository
+	AuditRepository
+}
+
+type AuditService struct {
+	auditRepository AuditRepository
+}
+
+func NewAudit(repository AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepository: repository,
+	}
 }
 
 type UserService struct {
 	userRepository UserRepository
+	audit          *AuditService
 }
 
-func NewUser(repository *postgres.Repository) *UserService {
+func NewUser(repository UserRepository, audit *AuditService) *UserService {
 	return &UserService{
 		userRepository: repository,
+		audit:          audit,
 	}
 }
 
 type ElectionService struct {
-	electionRepository ElectionRepository
+	electionRepository    ElectionRepository
+	translationRepository TranslationRepository
+	audit                 *AuditService
 }
 
-func NewElection(repository *postgres.Repository) *ElectionService {
+func NewElection(repository ElectionRepository, translationRepository TranslationRepository, audit *AuditService) *ElectionService {
 	return &ElectionService{
-		electionRepository: repository,
+		electionRepository:    repository,
+		translationRepository: translationRepository,
+		audit:                 audit,
 	}
 }
 
 type VoteVariantService struct {
 	voteVariantRepository VoteVariantRepository
+	electionRepository    ElectionRepository
+	translationRepository TranslationRepository
+	audit                 *AuditService
 }
 
-func NewVoteVariant(repository *postgres.Repository) *VoteVariantService {
+func NewVoteVariant(repository VoteVariantRepository, electionRepository ElectionRepository, translationRepository TranslationRepository, audit *AuditService) *VoteVariantService {
 	return &VoteVariantService{
 		voteVariantRepository: repository,
+		electionRepository:    electionRepository,
+		translationRepository: translationRepository,
+		audit:                 audit,
 	}
 }
 
@@ -76,24 +163,79 @@ type VoteService struct {
 	voteRepository VoteRepository
 }
 
-func NewVote(repository *postgres.Repository) *VoteService {
+func NewVote(repository VoteRepository) *VoteService {
 	return &VoteService{
 		voteRepository: repository,
 	}
 }
 
+type AuthService struct {
+	userRepository         UserRepository
+	roleRepository         RoleRepository
+	loginAttemptRepository LoginAttemptRepository
+	loginLockoutRepository LoginLockoutRepository
+	audit                  *AuditService
+	cfg                    config.AuthConfig
+}
+
+func NewAuth(userRepository UserRepository, roleRepository RoleRepository, loginLockoutRepository LoginLockoutRepository, loginAttemptRepository LoginAttemptRepository, audit *AuditService, cfg config.AuthConfig) *AuthService {
+	return &AuthService{
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		loginLockoutRepository: loginLockoutRepository,
+		audit:                  audit,
+		cfg:                    cfg,
+	}
+}
+
+type IdempotencyService struct {
+	idempotencyRepository IdempotencyRepository
+	cfg                   config.IdempotencyConfig
+}
+
+func NewIdempotency(repository IdempotencyRepository, cfg config.IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepository: repository,
+		cfg:                   cfg,
+	}
+}
+
 type Service struct {
+	softDeleteCfg config.SoftDeleteConfig
+
 	*UserService
 	*ElectionService
 	*VoteVariantService
 	*VoteService
+	*AuthService
+	*IdempotencyService
+	*AuditService
 }
 
-func New(userRepo, electionRepo, voteVariantRepo, voteRepo *postgres.Repository) *Service {
+func New(
+	userRepo UserRepository,
+	electionRepo ElectionRepository,
+	voteVariantRepo VoteVariantRepository,
+	voteRepo VoteRepository,
+	roleRepo RoleRepository,
+	loginLockoutRepo LoginLockoutRepository,
+	idempotencyRepo IdempotencyRepository,
+	auditRepo AuditRepository,
+	translationRepo TranslationRepository,
+	loginAttemptRepo LoginAttemptRepository,
+	cfg *config.Config,
+) *Service {
+	audit := NewAudit(auditRepo)
+
 	return &Service{
-		UserService:        NewUser(userRepo),
-		ElectionService:    NewElection(electionRepo),
-		VoteVariantService: NewVoteVariant(voteVariantRepo),
+		softDeleteCfg:      cfg.SoftDelete,
+		UserService:        NewUser(userRepo, audit),
+		ElectionService:    NewElection(electionRepo, translationRepo, audit),
+		VoteVariantService: NewVoteVariant(voteVariantRepo, electionRepo, translationRepo, audit),
 		VoteService:        NewVote(voteRepo),
+		AuthService:        NewAuth(userRepo, roleRepo, loginLockoutRepo, loginAttemptRepo, audit, cfg.Auth),
+		IdempotencyService: NewIdempotency(idempotencyRepo, cfg.Idempotency),
+		AuditService:       audit,
 	}
 }
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+func (s ElectionService) SetElectionTranslation(ctx context.Context, meta models.RequestMeta, electionID, locale, name, description string) (*models.ElectionTranslation, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.SetElectionTranslation")
+	defer span.End()
+
+	// Мягко удаленные выборы переводить нельзя, хотя внешний ключ это допускает
+	election, err := s.electionRepository.GetElection(ctx, electionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOwner(meta.Actor, election.UserID); err != nil {
+		return nil, err
+	}
+
+	translation, err := s.translationRepository.SetElectionTranslation(ctx, electionID, locale, name, description, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.audit.record(ctx, meta, models.AuditActionTranslate, models.AuditEntityElection, electionID, nil, translation)
+
+	return translation, nil
+}
+
+func (s ElectionService) DeleteElectionTranslation(ctx context.Context, meta models.RequestMeta, electionID, locale string) error {
+	ctx, span := tracer.Start(ctx, "ElectionService.DeleteElectionTranslation")
+	defer span.End()
+
+	election, err := s.electionRepository.GetElection(ctx, electionID)
+	if err != nil {
+		return err
+	}
+	if err := requireOwner(meta.Actor, election.UserID); err != nil {
+		return err
+	}
+
+	err = s.translationRepository.DeleteElectionTranslation(ctx, electionID, locale)
+	if err != nil {
+		return err
+	}
+	s.audit.record(ctx, meta, models.AuditActionUntranslate, models.AuditEntityElection, electionID, map[string]string{"locale": locale}, nil)
+
+	return nil
+}
+
+func (s ElectionService) GetElectionTranslations(ctx context.Context, electionID string) ([]*models.ElectionTranslation, error) {
+	ctx, span := tracer.Start(ctx, "ElectionService.GetElectionTranslations")
+	defer span.End()
+
+	if _, err := s.electionRepository.GetElection(ctx, electionID); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepository.GetElectionTranslations(ctx, []string{electionID}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+// Подставляет перевод на язык locale, если он есть; без перевода остается основной текст
+func (s ElectionService) localizeElections(ctx context.Context, locale string, elections ...*models.Election) error {
+	if locale == "" || len(elections) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(elections))
+	for _, election := range elections {
+		ids = append(ids, election.ID)
+	}
+
+	translations, err := s.translationRepository.GetElectionTranslations(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*models.ElectionTranslation, len(translations))
+	for _, translation := range translations {
+		byID[translation.ElectionID] = translation
+	}
+	for _, election := range elections {
+		if translation, ok := byID[election.ID]; ok {
+			election.Name = translation.Name
+			election.Description = translation.Description
+		}
+	}
+
+	return nil
+}
+
+func (s VoteVariantService) SetVoteVariantTranslation(ctx context.Context, meta models.RequestMeta, voteVariantID, locale, name string) (*models.VoteVariantTranslation, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.SetVoteVariantTranslation")
+	defer span.End()
+
+	voteVariant, err := s.voteVariantRepository.GetVoteVariant(ctx, voteVariantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireElectionOwner(ctx, meta.Actor, voteVariant.ElectionID); err != nil {
+		return nil, err
+	}
+
+	translation, err := s.translationRepository.SetVoteVariantTranslation(ctx, voteVariantID, locale, name, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	s.audit.record(ctx, meta, models.AuditActionTranslate, models.AuditEntityVoteVariant, voteVariantID, nil, translation)
+
+	return translation, nil
+}
+
+func (s VoteVariantService) DeleteVoteVariantTranslation(ctx context.Context, meta models.RequestMeta, voteVariantID, locale string) error {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.DeleteVoteVariantTranslation")
+	defer span.End()
+
+	voteVariant, err := s.voteVariantRepository.GetVoteVariant(ctx, voteVariantID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireElectionOwner(ctx, meta.Actor, voteVariant.ElectionID); err != nil {
+		return err
+	}
+
+	err = s.translationRepository.DeleteVoteVariantTranslation(ctx, voteVariantID, locale)
+	if err != nil {
+		return err
+	}
+	s.audit.record(ctx, meta, models.AuditActionUntranslate, models.AuditEntityVoteVariant, voteVariantID, map[string]string{"locale": locale}, nil)
+
+	return nil
+}
+
+func (s VoteVariantService) GetVoteVariantTranslations(ctx context.Context, voteVariantID string) ([]*models.VoteVariantTranslation, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.GetVoteVariantTranslations")
+	defer span.End()
+
+	if _, err := s.voteVariantRepository.GetVoteVariant(ctx, voteVariantID); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepository.GetVoteVariantTranslations(ctx, []string{voteVariantID}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (s VoteVariantService) localizeVoteVariants(ctx context.Context, locale string, voteVariants ...*models.VoteVariant) error {
+	if locale == "" || len(voteVariants) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(voteVariants))
+	for _, voteVariant := range voteVariants {
+		ids = append(ids, voteVariant.ID)
+	}
+
+	translations, err := s.translationRepository.GetVoteVariantTranslations(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*models.VoteVariantTranslation, len(translations))
+	for _, translation := range translations {
+		byID[translation.VoteVariantID] = translation
+	}
+	for _, voteVariant := range voteVariants {
+		if translation, ok := byID[voteVariant.ID]; ok {
+			voteVariant.Name = translation.Name
+		}
+	}
+
+	return nil
+}
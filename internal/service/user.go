@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"time"
 
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
@@ -9,7 +10,10 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s UserService) CreateUser(nickname, password string) (*models.User, error) {
+func (s UserService) CreateUser(ctx context.Context, meta models.RequestMeta, nickname, password string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
 	id := uuid.New().String()
 	now := time.Now()
 
@@ -18,19 +22,27 @@ func (s UserService) CreateUser(nickname, password string) (*models.User, error)
 		return nil, apperrors.ErrFailedToHashPassword
 	}
 
-	user, err := s.userRepository.CreateUser(id, nickname, string(hashedPassword), now, now)
+	user, err := s.userRepository.CreateUser(ctx, id, nickname, string(hashedPassword), now, now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionCreate, models.AuditEntityUser, user.ID, nil, user)
 
 	return user, nil
 }
 
-func (s UserService) GetUsers(limit, offset int) ([]*models.User, error) {
+func (s UserService) GetUsers(ctx context.Context, actor *models.User, limit, offset int) ([]*models.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUsers")
+	defer span.End()
+
+	if err := requireRole(actor, models.RoleModerator, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
 	validLimit := validateLimit(limit)
 	validOffset := validateOffset(offset)
 
-	users, err := s.userRepository.GetUsers(validLimit, validOffset)
+	users, err := s.userRepository.GetUsers(ctx, validLimit, validOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -38,8 +50,11 @@ func (s UserService) GetUsers(limit, offset int) ([]*models.User, error) {
 	return users, nil
 }
 
-func (s UserService) GetUser(uuid string) (*models.User, error) {
-	user, err := s.userRepository.GetUser(uuid)
+func (s UserService) GetUser(ctx context.Context, uuid string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUser")
+	defer span.End()
+
+	user, err := s.userRepository.GetUser(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
@@ -47,51 +62,75 @@ func (s UserService) GetUser(uuid string) (*models.User, error) {
 	return user, nil
 }
 
-func (s UserService) UpdateUser(uuid, nickname, password string) (*models.User, error) {
+func (s UserService) UpdateUser(ctx context.Context, meta models.RequestMeta, uuid, nickname string, versions []int64) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
 	now := time.Now()
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := requireOwner(meta.Actor, uuid); err != nil {
+		return nil, err
+	}
+
+	before, err := s.userRepository.GetUser(ctx, uuid)
 	if err != nil {
-		return nil, apperrors.ErrFailedToHashPassword
+		return nil, err
 	}
 
-	user, err := s.userRepository.UpdateUser(uuid, nickname, string(hashedPassword), now)
+	user, err := s.userRepository.UpdateUser(ctx, uuid, nickname, matchVersion(versions, before.Version), now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionUpdate, models.AuditEntityUser, user.ID, before, user)
 
 	return user, nil
 }
 
-func (s UserService) DeleteUser(uuid string) error {
-	err := s.userRepository.DeleteUser(uuid)
+func (s UserService) DeleteUser(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error {
+	ctx, span := tracer.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+
+	if err := requireOwner(meta.Actor, uuid); err != nil {
+		return err
+	}
+
+	before, err := s.userRepository.GetUser(ctx, uuid)
+	if err != nil {
+		return err
+	}
+
+	err = s.userRepository.DeleteUser(ctx, uuid, matchVersion(versions, before.Version), time.Now())
 	if err != nil {
 		return err
 	}
+	s.audit.record(ctx, meta, models.AuditActionDelete, models.AuditEntityUser, uuid, before, nil)
 
 	return nil
 }
 
-func (s UserService) PatchUser(uuid string, nickname, password *string) (*models.User, error) {
+func (s UserService) PatchUser(ctx context.Context, meta models.RequestMeta, uuid string, nickname *string, versions []int64) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.PatchUser")
+	defer span.End()
+
 	now := time.Now()
-	if nickname == nil && password == nil {
+	if nickname == nil {
 		return nil, apperrors.ErrNothingToChange
 	}
 
-	var hashedPassword *string
-	if password != nil {
-		hashed, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
-		if err != nil {
-			return nil, apperrors.ErrFailedToHashPassword
-		}
-		hashedStr := string(hashed)
-		hashedPassword = &hashedStr
+	if err := requireOwner(meta.Actor, uuid); err != nil {
+		return nil, err
+	}
+
+	before, err := s.userRepository.GetUser(ctx, uuid)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := s.userRepository.PatchUser(uuid, nickname, hashedPassword, now)
+	user, err := s.userRepository.PatchUser(ctx, uuid, nickname, matchVersion(versions, before.Version), now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionPatch, models.AuditEntityUser, user.ID, before, user)
 
 	return user, nil
 }
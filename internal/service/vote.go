@@ -1,18 +1,16 @@
 package service
 
 import (
-	"time"
+	"context"
 
-	apperrors "github.com/alonsoF100/golos/internal/erorrs"
 	"github.com/alonsoF100/golos/internal/models"
-	"github.com/google/uuid"
 )
 
-func (s VoteService) CreateVote(userID, voteVariantID string) (*models.Vote, error) {
-	id := uuid.New().String()
-	now := time.Now()
+func (s VoteService) GetVote(ctx context.Context, voteID string) (*models.Vote, error) {
+	ctx, span := tracer.Start(ctx, "VoteService.GetVote")
+	defer span.End()
 
-	vote, err := s.voteRepository.CreateVote(id, userID, voteVariantID, now, now)
+	vote, err := s.voteRepository.GetVote(ctx, voteID)
 	if err != nil {
 		return nil, err
 	}
@@ -20,35 +18,20 @@ func (s VoteService) CreateVote(userID, voteVariantID string) (*models.Vote, err
 	return vote, nil
 }
 
-func (s VoteService) GetVote(voteID string) (*models.Vote, error) {
-	vote, err := s.voteRepository.GetVote(voteID)
-	if err != nil {
-		return nil, err
-	}
-
-	return vote, nil
-}
-
-func (s VoteService) DeleteVote(voteID string) error {
-	err := s.voteRepository.DeleteVote(voteID)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (s VoteService) PatchVote(voteID string, userID, voteVariantID *string) (*models.Vote, error) {
-	now := time.Now()
+func (s VoteService) GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error) {
+	ctx, span := tracer.Start(ctx, "VoteService.GetVoteHistory")
+	defer span.End()
 
-	if userID == nil && voteVariantID == nil {
-		return nil, apperrors.ErrNothingToChange
-	}
-
-	vote, err := s.voteRepository.PatchVote(voteID, userID, voteVariantID, now)
+	// История отозванного голоса сохраняется, поэтому голос ищется только при пустой истории
+	history, err := s.voteRepository.GetVoteHistory(ctx, voteID)
 	if err != nil {
 		return nil, err
 	}
+	if len(history) == 0 {
+		if _, err := s.voteRepository.GetVote(ctx, voteID); err != nil {
+			return nil, err
+		}
+	}
 
-	return vote, nil
+	return history, nil
 }
@@ -1,58 +1,120 @@
 package service
 
 import (
+	"context"
 	"time"
 
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
 	"github.com/alonsoF100/golos/internal/models"
 	"github.com/google/uuid"
 )
 
-func (s VoteVariantService) CreateVoteVariant(electionID, name string) (*models.VoteVariant, error) {
+func (s VoteVariantService) CreateVoteVariant(ctx context.Context, meta models.RequestMeta, electionID, name string) (*models.VoteVariant, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.CreateVoteVariant")
+	defer span.End()
+
+	if err := s.requireElectionOwner(ctx, meta.Actor, electionID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	id := uuid.New().String()
 
-	voteVariant, err := s.voteVariantRepository.CreateVoteVariant(id, electionID, name, now, now)
+	voteVariant, err := s.voteVariantRepository.CreateVoteVariant(ctx, id, electionID, name, now, now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionCreate, models.AuditEntityVoteVariant, voteVariant.ID, nil, voteVariant)
 
 	return voteVariant, nil
 }
 
-func (s VoteVariantService) GetVoteVariants(electionID string) ([]*models.VoteVariant, error) {
-	voteVariants, err := s.voteVariantRepository.GetVoteVariants(electionID)
+func (s VoteVariantService) GetVoteVariants(ctx context.Context, electionID, locale string) ([]*models.VoteVariant, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.GetVoteVariants")
+	defer span.End()
+
+	voteVariants, err := s.voteVariantRepository.GetVoteVariants(ctx, electionID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.localizeVoteVariants(ctx, locale, voteVariants...); err != nil {
+		return nil, err
+	}
+
 	return voteVariants, nil
 }
 
-func (s VoteVariantService) GetVoteVariant(uuid string) (*models.VoteVariant, error) {
-	voteVariant, err := s.voteVariantRepository.GetVoteVariant(uuid)
+func (s VoteVariantService) GetVoteVariant(ctx context.Context, uuid, locale string) (*models.VoteVariant, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.GetVoteVariant")
+	defer span.End()
+
+	voteVariant, err := s.voteVariantRepository.GetVoteVariant(ctx, uuid)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.localizeVoteVariants(ctx, locale, voteVariant); err != nil {
+		return nil, err
+	}
+
 	return voteVariant, nil
 }
 
-func (s VoteVariantService) DeleteVoteVariant(uuid string) error {
-	err := s.voteVariantRepository.DeleteVoteVariant(uuid)
+func (s VoteVariantService) DeleteVoteVariant(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.DeleteVoteVariant")
+	defer span.End()
+
+	before, err := s.voteVariantRepository.GetVoteVariant(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if err := s.requireElectionOwner(ctx, meta.Actor, before.ElectionID); err != nil {
+		return err
+	}
+
+	err = s.voteVariantRepository.DeleteVoteVariant(ctx, uuid, matchVersion(versions, before.Version), time.Now())
 	if err != nil {
 		return err
 	}
+	s.audit.record(ctx, meta, models.AuditActionDelete, models.AuditEntityVoteVariant, uuid, before, nil)
 
 	return nil
 }
 
-func (s VoteVariantService) UpdateVoteVariant(uuid string, name string) (*models.VoteVariant, error) {
+func (s VoteVariantService) UpdateVoteVariant(ctx context.Context, meta models.RequestMeta, uuid string, name string, versions []int64) (*models.VoteVariant, error) {
+	ctx, span := tracer.Start(ctx, "VoteVariantService.UpdateVoteVariant")
+	defer span.End()
+
 	now := time.Now()
 
-	voteVariant, err := s.voteVariantRepository.UpdateVoteVariant(uuid, name, now)
+	before, err := s.voteVariantRepository.GetVoteVariant(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireElectionOwner(ctx, meta.Actor, before.ElectionID); err != nil {
+		return nil, err
+	}
+
+	voteVariant, err := s.voteVariantRepository.UpdateVoteVariant(ctx, uuid, name, matchVersion(versions, before.Version), now)
 	if err != nil {
 		return nil, err
 	}
+	s.audit.record(ctx, meta, models.AuditActionUpdate, models.AuditEntityVoteVariant, voteVariant.ID, before, voteVariant)
 
 	return voteVariant, nil
 }
+
+// Варианты принадлежат владельцу выборов
+func (s VoteVariantService) requireElectionOwner(ctx context.Context, actor *models.User, electionID string) error {
+	if actor == nil {
+		return apperrors.ErrUnauthorized
+	}
+
+	election, err := s.electionRepository.GetElection(ctx, electionID)
+	if err != nil {
+		return err
+	}
+
+	return requireOwner(actor, election.UserID)
+}
@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/alonsoF100/golos/internal/models"
+)
+
 type UserRequest struct {
 	Nickname string `json:"nickname" validate:"required,alphanum,min=3,max=12"`
 	Password string `json:"password" validate:"required,min=5,max=20"`
@@ -12,20 +18,30 @@ type UserID struct {
 type UserUpdate struct {
 	ID       string `json:"id" validate:"required,uuid"`
 	Nickname string `json:"nickname" validate:"required,alphanum,min=3,max=12"`
-	Password string `json:"password" validate:"required,min=5,max=20"`
 }
 
 type UserPatch struct {
 	ID       string  `json:"id" validate:"required,uuid"`
 	Nickname *string `json:"nickname,omitempty" validate:"omitempty,alphanum,min=3,max=12"`
-	Password *string `json:"password,omitempty" validate:"omitempty,min=5,max=20"`
+}
+
+type UserPasswordChange struct {
+	ID              string `json:"id" validate:"required,uuid"`
+	CurrentPassword string `json:"current_password" validate:"required,min=5,max=20"`
+	NewPassword     string `json:"new_password" validate:"required,min=5,max=20,nefield=CurrentPassword"`
+}
+
+type UserRoleRequest struct {
+	ID   string `json:"id" validate:"required,uuid"`
+	Role string `json:"role" validate:"required,oneof=user moderator admin"`
 }
 
 // elections dto
 type ElectionRequest struct {
 	UserID      string `json:"user_id" validate:"required,uuid"`
 	Name        string `json:"name" validate:"alphanum,min=3,max=50"`
-	Description string `json:"description" validate:"required,min=3,max=100"`
+	Description string `json:"description" validate:"required,notblank,min=3,max=100"`
+	VotePolicy  string `json:"vote_policy,omitempty" validate:"omitempty,oneof=final changes_until_close"`
 }
 
 type ElectionID struct {
@@ -36,11 +52,71 @@ type ElectionPatch struct {
 	ID          string  `json:"id" validate:"required,uuid"`
 	UserID      *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
 	Name        *string `json:"name,omitempty" validate:"omitempty,alphanum,min=3,max=50"`
-	Description *string `json:"description,omitempty" validate:"omitempty,min=3,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,notblank,min=3,max=100"`
+	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=open closed"`
+	VotePolicy  *string `json:"vote_policy,omitempty" validate:"omitempty,oneof=final changes_until_close"`
+}
+
+type ElectionFilter struct {
+	Query         string `query:"q" validate:"omitempty,max=100"`
+	Status        string `query:"status" validate:"omitempty,oneof=open closed"`
+	CreatedAfter  string `query:"created_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	CreatedBefore string `query:"created_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00,notbefore=CreatedAfter"`
+	SortBy        string `query:"sort" validate:"omitempty,oneof=created_at updated_at name relevance"`
+	SortOrder     string `query:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// Вызывать только после валидации, ошибки парсинга дат здесь уже невозможны
+func (f ElectionFilter) ToModel() models.ElectionFilter {
+	filter := models.ElectionFilter{
+		Query:     f.Query,
+		Status:    f.Status,
+		SortBy:    f.SortBy,
+		SortOrder: f.SortOrder,
+	}
+	if t, err := time.Parse(time.RFC3339, f.CreatedAfter); err == nil {
+		filter.CreatedAfter = &t
+	}
+	if t, err := time.Parse(time.RFC3339, f.CreatedBefore); err == nil {
+		filter.CreatedBefore = &t
+	}
+
+	return filter
 }
 
 type GetElections struct {
-	Nickname string `validate:"required,alphanum,min=3,max=12"`
+	Nickname string `query:"nickname" validate:"required,alphanum,min=3,max=12"`
+	ElectionFilter
+}
+
+type SearchElections struct {
+	ElectionFilter
+}
+
+// audit dtos
+type AuditFilter struct {
+	EntityType string `query:"entity_type" validate:"omitempty,oneof=user election vote_variant vote purge"`
+	EntityID   string `query:"entity_id" validate:"omitempty,max=64"`
+	ActorID    string `query:"actor_id" validate:"omitempty,uuid"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00,notbefore=From"`
+}
+
+// Вызывать только после валидации, как и ElectionFilter.ToModel
+func (f AuditFilter) ToModel() models.AuditFilter {
+	filter := models.AuditFilter{
+		EntityType: f.EntityType,
+		EntityID:   f.EntityID,
+		ActorID:    f.ActorID,
+	}
+	if t, err := time.Parse(time.RFC3339, f.From); err == nil {
+		filter.From = &t
+	}
+	if t, err := time.Parse(time.RFC3339, f.To); err == nil {
+		filter.To = &t
+	}
+
+	return filter
 }
 
 // Vote Variant DTOs
@@ -53,13 +129,37 @@ type VoteVariantID struct {
 	ID string `json:"id" validate:"required,uuid"`
 }
 
+// Перевод на язык locale, не только латиница, поэтому без alphanum
+type ElectionTranslationRequest struct {
+	ID          string `json:"id" validate:"required,uuid"`
+	Locale      string `json:"locale" validate:"required,oneof=en ru"`
+	Name        string `json:"name" validate:"required,notblank,min=3,max=50"`
+	Description string `json:"description" validate:"required,notblank,min=3,max=100"`
+}
+
+type ElectionTranslationID struct {
+	ID     string `json:"id" validate:"required,uuid"`
+	Locale string `json:"locale" validate:"required,oneof=en ru"`
+}
+
+type VoteVariantTranslationRequest struct {
+	ID     string `json:"id" validate:"required,uuid"`
+	Locale string `json:"locale" validate:"required,oneof=en ru"`
+	Name   string `json:"name" validate:"required,notblank,min=1,max=50"`
+}
+
+type VoteVariantTranslationID struct {
+	ID     string `json:"id" validate:"required,uuid"`
+	Locale string `json:"locale" validate:"required,oneof=en ru"`
+}
+
 type VoteVariantUpdate struct {
 	ID   string `json:"id" validate:"required,uuid"`
 	Name string `json:"name" validate:"required,alphanum,min=1,max=50"`
 }
 
 type GetVoteVariantsRequest struct {
-	ElectionID string `validate:"required,uuid"`
+	ElectionID string `query:"election_id" validate:"required,uuid"`
 }
 
 // vote dtos
@@ -73,12 +173,12 @@ type VoteID struct {
 }
 
 type VotePatch struct {
-	ID        string `json:"id"`
-	VariantID string `json:"variant_id"`
-	UserID    string `json:"user_id"`
+	ID        string  `json:"id" validate:"required,uuid"`
+	VariantID *string `json:"variant_id,omitempty" validate:"omitempty,uuid"`
+	UserID    *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
 }
 
 type GetUserVotes struct {
-	Nickname   string `validate:"required,alphanum,min=3,max=12"`
-	ElectionID string `validate:"omitempty,uuid"`
+	Nickname   string `query:"nickname" validate:"required,alphanum,min=3,max=12"`
+	ElectionID string `query:"election_id" validate:"omitempty,uuid"`
 }
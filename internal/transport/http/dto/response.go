@@ -1,59 +1,111 @@
 package dto
 
 import (
+	"encoding/json"
+	"net/http"
 	"time"
 
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/health"
+	"github.com/alonsoF100/golos/internal/i18n"
 	"github.com/alonsoF100/golos/internal/models"
 )
 
-type ErrorResponse struct {
-	Error     string    `json:"error"`
-	TimeStamp time.Time `json:"timestamp"`
+// Ошибка в формате RFC 7807 (application/problem+json), code - машиночитаемый код
+type ProblemResponse struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail"`
+	Instance  string                 `json:"instance,omitempty"`
+	Code      string                 `json:"code"`
+	Errors    []FieldProblemResponse `json:"errors,omitempty"`
+	TimeStamp time.Time              `json:"timestamp"`
 }
 
-func NewErrorResponse(err error) ErrorResponse {
-	return ErrorResponse{
-		Error:     err.Error(),
+type FieldProblemResponse struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Текст ошибки берется из каталога языка locale, причина добавляется только для 4xx
+func NewProblemResponse(err *apperrors.AppError, locale string, instance string) ProblemResponse {
+	detail := i18n.Format(locale, "error."+err.Code, err.Message, nil)
+	if err.Cause != nil && err.Status < http.StatusInternalServerError {
+		detail += ": " + err.Cause.Error()
+	}
+
+	response := ProblemResponse{
+		Type:      "urn:golos:error:" + err.Code,
+		Title:     http.StatusText(err.Status),
+		Status:    err.Status,
+		Detail:    detail,
+		Instance:  instance,
+		Code:      err.Code,
 		TimeStamp: time.Now(),
 	}
+	for _, field := range err.Fields {
+		response.Errors = append(response.Errors, FieldProblemResponse{
+			Field:   field.Field,
+			Rule:    field.Rule,
+			Param:   field.Param,
+			Message: field.Message,
+		})
+	}
+
+	return response
 }
 
+// Публичное представление пользователя, видно всем
 type UserResponse struct {
 	ID        string    `json:"id"`
 	Nickname  string    `json:"nickname"`
-	Password  string    `json:"password"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func NewUserResponse(user *models.User) UserResponse {
 	return UserResponse{
 		ID:        user.ID,
 		Nickname:  user.Nickname,
-		Password:  user.Password,
-		CreatedAt: user.UpdatedAt,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// Представление для самого пользователя и модераторов
+type UserSelfResponse struct {
+	ID        string    `json:"id"`
+	Nickname  string    `json:"nickname"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"version"`
+}
+
+func NewUserSelfResponse(user *models.User) UserSelfResponse {
+	return UserSelfResponse{
+		ID:        user.ID,
+		Nickname:  user.Nickname,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+		Version:   user.Version,
 	}
 }
 
 type UsersResponse struct {
-	Users []*UserResponse
+	Users []*UserSelfResponse
 }
 
 func NewUsersResponse(users []*models.User) UsersResponse {
 	responseUsers := UsersResponse{
-		Users: make([]*UserResponse, 0, len(users)),
+		Users: make([]*UserSelfResponse, 0, len(users)),
 	}
 
 	for _, user := range users {
-		temp := &UserResponse{
-			ID:        user.ID,
-			Nickname:  user.Nickname,
-			Password:  user.Password,
-			CreatedAt: user.UpdatedAt,
-			UpdatedAt: user.UpdatedAt,
-		}
-		responseUsers.Users = append(responseUsers.Users, temp)
+		temp := NewUserSelfResponse(user)
+		responseUsers.Users = append(responseUsers.Users, &temp)
 	}
 
 	return responseUsers
@@ -65,8 +117,11 @@ type ElectionResponse struct {
 	UserID      string    `json:"user_id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	VotePolicy  string    `json:"vote_policy"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int64     `json:"version"`
 }
 
 func NewElectionResponse(election *models.Election) ElectionResponse {
@@ -75,8 +130,11 @@ func NewElectionResponse(election *models.Election) ElectionResponse {
 		UserID:      election.UserID,
 		Name:        election.Name,
 		Description: election.Description,
+		Status:      election.Status,
+		VotePolicy:  election.VotePolicy,
 		CreatedAt:   election.CreatedAt,
 		UpdatedAt:   election.UpdatedAt,
+		Version:     election.Version,
 	}
 }
 
@@ -94,8 +152,11 @@ func NewElectionsResponse(elections []*models.Election) ElectionsResponse {
 			UserID:      election.UserID,
 			Name:        election.Name,
 			Description: election.Description,
+			Status:      election.Status,
+			VotePolicy:  election.VotePolicy,
 			CreatedAt:   election.CreatedAt,
 			UpdatedAt:   election.UpdatedAt,
+			Version:     election.Version,
 		}
 		responseElections.Elections = append(responseElections.Elections, temp)
 	}
@@ -109,6 +170,7 @@ type VoteVariantResponse struct {
 	Name       string    `json:"name"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+	Version    int64     `json:"version"`
 }
 
 func NewVoteVariantResponse(voteVariant *models.VoteVariant) VoteVariantResponse {
@@ -118,6 +180,7 @@ func NewVoteVariantResponse(voteVariant *models.VoteVariant) VoteVariantResponse
 		Name:       voteVariant.Name,
 		CreatedAt:  voteVariant.CreatedAt,
 		UpdatedAt:  voteVariant.UpdatedAt,
+		Version:    voteVariant.Version,
 	}
 }
 
@@ -136,12 +199,83 @@ func NewVoteVariantsResponse(voteVariants []*models.VoteVariant) VoteVariantsRes
 			Name:       variant.Name,
 			CreatedAt:  variant.CreatedAt,
 			UpdatedAt:  variant.UpdatedAt,
+			Version:    variant.Version,
 		}
 		responseVariants.VoteVariants = append(responseVariants.VoteVariants, temp)
 	}
 	return responseVariants
 }
 
+type ElectionTranslationResponse struct {
+	ElectionID  string    `json:"election_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewElectionTranslationResponse(translation *models.ElectionTranslation) ElectionTranslationResponse {
+	return ElectionTranslationResponse{
+		ElectionID:  translation.ElectionID,
+		Locale:      translation.Locale,
+		Name:        translation.Name,
+		Description: translation.Description,
+		CreatedAt:   translation.CreatedAt,
+		UpdatedAt:   translation.UpdatedAt,
+	}
+}
+
+type ElectionTranslationsResponse struct {
+	Translations []*ElectionTranslationResponse `json:"translations"`
+}
+
+func NewElectionTranslationsResponse(translations []*models.ElectionTranslation) ElectionTranslationsResponse {
+	response := ElectionTranslationsResponse{
+		Translations: make([]*ElectionTranslationResponse, 0, len(translations)),
+	}
+	for _, translation := range translations {
+		temp := NewElectionTranslationResponse(translation)
+		response.Translations = append(response.Translations, &temp)
+	}
+
+	return response
+}
+
+type VoteVariantTranslationResponse struct {
+	VoteVariantID string    `json:"vote_variant_id"`
+	Locale        string    `json:"locale"`
+	Name          string    `json:"name"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewVoteVariantTranslationResponse(translation *models.VoteVariantTranslation) VoteVariantTranslationResponse {
+	return VoteVariantTranslationResponse{
+		VoteVariantID: translation.VoteVariantID,
+		Locale:        translation.Locale,
+		Name:          translation.Name,
+		CreatedAt:     translation.CreatedAt,
+		UpdatedAt:     translation.UpdatedAt,
+	}
+}
+
+type VoteVariantTranslationsResponse struct {
+	Translations []*VoteVariantTranslationResponse `json:"translations"`
+}
+
+func NewVoteVariantTranslationsResponse(translations []*models.VoteVariantTranslation) VoteVariantTranslationsResponse {
+	response := VoteVariantTranslationsResponse{
+		Translations: make([]*VoteVariantTranslationResponse, 0, len(translations)),
+	}
+	for _, translation := range translations {
+		temp := NewVoteVariantTranslationResponse(translation)
+		response.Translations = append(response.Translations, &temp)
+	}
+
+	return response
+}
+
 type VoteResponse struct {
 	ID        string    `json:"id"`
 	VariantID string    `json:"variant_id"`
@@ -181,3 +315,122 @@ func NewVotesResponse(votes []*models.Vote) VotesResponse {
 
 	return responseVariants
 }
+
+type VoteChangeResponse struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	OldVariantID string    `json:"old_variant_id,omitempty"`
+	NewVariantID string    `json:"new_variant_id,omitempty"`
+	ChangedAt    time.Time `json:"changed_at"`
+}
+
+type VoteHistoryResponse struct {
+	VoteID  string                `json:"vote_id"`
+	Changes []*VoteChangeResponse `json:"changes"`
+}
+
+func NewVoteHistoryResponse(voteID string, changes []*models.VoteChange) VoteHistoryResponse {
+	response := VoteHistoryResponse{
+		VoteID:  voteID,
+		Changes: make([]*VoteChangeResponse, 0, len(changes)),
+	}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, &VoteChangeResponse{
+			ID:           change.ID,
+			UserID:       change.UserID,
+			OldVariantID: change.OldVariantID,
+			NewVariantID: change.NewVariantID,
+			ChangedAt:    change.ChangedAt,
+		})
+	}
+
+	return response
+}
+
+type PurgeResponse struct {
+	Users        int64 `json:"users"`
+	Elections    int64 `json:"elections"`
+	VoteVariants int64 `json:"vote_variants"`
+}
+
+func NewPurgeResponse(result *models.PurgeResult) PurgeResponse {
+	return PurgeResponse{
+		Users:        result.Users,
+		Elections:    result.Elections,
+		VoteVariants: result.VoteVariants,
+	}
+}
+
+// audit dto
+type AuditEventResponse struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditEventsResponse struct {
+	Events []*AuditEventResponse `json:"events"`
+}
+
+func NewAuditEventsResponse(events []*models.AuditEvent) AuditEventsResponse {
+	response := AuditEventsResponse{
+		Events: make([]*AuditEventResponse, 0, len(events)),
+	}
+
+	for _, event := range events {
+		response.Events = append(response.Events, &AuditEventResponse{
+			ID:         event.ID,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			EntityType: event.EntityType,
+			EntityID:   event.EntityID,
+			Before:     event.Before,
+			After:      event.After,
+			RequestID:  event.RequestID,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return response
+}
+
+type HealthResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func NewHealthResponse(ready bool, results map[string]health.Result) HealthResponse {
+	status := health.StatusUp
+	if !ready {
+		status = health.StatusDown
+	}
+
+	checks := make(map[string]HealthCheckResponse, len(results))
+	for name, result := range results {
+		check := HealthCheckResponse{
+			Status:    result.Status,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Err != nil {
+			check.Error = result.Err.Error()
+		}
+		checks[name] = check
+	}
+
+	return HealthResponse{
+		Status: status,
+		Checks: checks,
+	}
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Поля, которые ни при каких условиях не должны попадать в ответы API
+var credentialFields = []string{"password", "hash", "secret", "token", "salt"}
+
+func TestResponsesDoNotExposeCredentials(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("parse dto package: %v", err)
+	}
+
+	checked := 0
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok || !strings.HasSuffix(spec.Name.Name, "Response") {
+					return true
+				}
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+
+				checked++
+				for _, field := range st.Fields.List {
+					for _, name := range fieldNames(field) {
+						if isCredential(name) {
+							t.Errorf("%s exposes credential field %q", spec.Name.Name, name)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	if checked == 0 {
+		t.Fatal("no response types found in dto package")
+	}
+}
+
+func fieldNames(field *ast.Field) []string {
+	var names []string
+	for _, ident := range field.Names {
+		names = append(names, ident.Name)
+	}
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		if jsonName, _, _ := strings.Cut(tag.Get("json"), ","); jsonName != "" {
+			names = append(names, jsonName)
+		}
+	}
+
+	return names
+}
+
+func isCredential(name string) bool {
+	name = strings.ToLower(name)
+	for _, credential := range credentialFields {
+		if strings.Contains(name, credential) {
+			return true
+		}
+	}
+
+	return false
+}
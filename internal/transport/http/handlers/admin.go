@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+pattern: /golos/admin/users/{id}/role
+method:  PUT
+info:    UUID from pattern + JSON in request body ({"role": "user|moderator|admin"}), admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented user with new role
+
+failed:
+  - status code:   400, 401, 403, 404, 409, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
+		return
+	}
+
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	user, err := h.service.SetUserRole(r.Context(), RequestMetaFromRequest(r), req.ID, req.Role)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewUserSelfResponse(user))
+}
+
+/*
+pattern: /golos/admin/elections/{id}/close
+method:  POST
+info:    UUID from pattern, moderator or admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented closed election
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) CloseElection(w http.ResponseWriter, r *http.Request) {
+	var req dto.ElectionID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	election, err := h.service.CloseElection(r.Context(), RequestMetaFromRequest(r), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	SetETag(w, election.Version)
+	WriteJSON(w, http.StatusOK, dto.NewElectionResponse(election))
+}
+
+/*
+pattern: /golos/admin/votes/{id}
+method:  DELETE
+info:    UUID from pattern, moderator or admin only
+
+succeed:
+  - status code:   204 no content
+  - response body: -
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RemoveVote(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err := h.service.RemoveVote(r.Context(), RequestMetaFromRequest(r), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusNoContent, nil)
+}
+
+/*
+pattern: /golos/users/{id}/restore
+method:  POST
+info:    UUID from pattern, moderator or admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented restored user
+
+failed:
+  - status code:   400, 401, 403, 404, 409, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	user, err := h.service.RestoreUser(r.Context(), RequestMetaFromRequest(r), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	SetETag(w, user.Version)
+	WriteJSON(w, http.StatusOK, dto.NewUserSelfResponse(user))
+}
+
+/*
+pattern: /golos/elections/{id}/restore
+method:  POST
+info:    UUID from pattern, moderator or admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented restored election
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RestoreElection(w http.ResponseWriter, r *http.Request) {
+	var req dto.ElectionID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	election, err := h.service.RestoreElection(r.Context(), RequestMetaFromRequest(r), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	SetETag(w, election.Version)
+	WriteJSON(w, http.StatusOK, dto.NewElectionResponse(election))
+}
+
+/*
+pattern: /golos/vote_variants/{id}/restore
+method:  POST
+info:    UUID from pattern, moderator or admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented restored vote variant
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RestoreVoteVariant(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteVariantID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	voteVariant, err := h.service.RestoreVoteVariant(r.Context(), RequestMetaFromRequest(r), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	SetETag(w, voteVariant.Version)
+	WriteJSON(w, http.StatusOK, dto.NewVoteVariantResponse(voteVariant))
+}
+
+/*
+pattern: /golos/admin/purge
+method:  POST
+info:    hard-deletes records soft-deleted longer than the retention period, admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON with purged counts
+
+failed:
+  - status code:   401, 403, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) PurgeDeleted(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.PurgeDeleted(r.Context(), RequestMetaFromRequest(r))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewPurgeResponse(result))
+}
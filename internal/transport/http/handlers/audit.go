@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+)
+
+/*
+pattern: /golos/audit
+method:  GET
+info:    query params: entity_type, entity_id, actor_id, from, to (RFC3339), limit, offset; moderator or admin only
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented audit events, newest first
+
+failed:
+  - status code:   400, 401, 403, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) GetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var err error
+	limitStr := query.Get("limit")
+	offsetStr := query.Get("offset")
+	var limit, offset int
+
+	if limitStr == "" {
+		limit = 20
+	} else {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			WriteError(w, r, apperrors.ErrInvalidLimit)
+			return
+		}
+	}
+
+	if offsetStr == "" {
+		offset = 0
+	} else {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			WriteError(w, r, apperrors.ErrInvalidOffset)
+			return
+		}
+	}
+
+	req := dto.AuditFilter{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		ActorID:    query.Get("actor_id"),
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	events, err := h.service.GetAuditEvents(r.Context(), UserFromContext(r.Context()), limit, offset, req.ToModel())
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewAuditEventsResponse(events))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/alonsoF100/golos/internal/models"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// Возвращает аутентифицированного пользователя или nil для анонимного запроса
+func UserFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userContextKey).(*models.User)
+	return user
+}
+
+/*
+middleware: HTTP Basic (nickname:password)
+info:       without credentials the request goes on as anonymous
+
+failed:
+  - status code:   401 for wrong credentials, 423 for locked account, 429 for too many attempts from ip
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nickname, password, ok := r.BasicAuth()
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := h.service.Authenticate(r.Context(), nickname, password, ClientIP(r))
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userContextKey, user)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("user_id", user.ID))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+/*
+middleware: allows the request only for an authenticated user
+info:       ownership is checked by the service
+
+failed:
+  - status code:   401
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if UserFromContext(r.Context()) == nil {
+			WriteError(w, r, apperrors.ErrUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+/*
+middleware: allows the request only for one of the given roles
+
+failed:
+  - status code:   401, 403
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				WriteError(w, r, apperrors.ErrUnauthorized)
+				return
+			}
+			if !slices.Contains(roles, user.Role) {
+				WriteError(w, r, apperrors.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
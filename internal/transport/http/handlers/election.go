@@ -2,11 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/i18n"
 	"github.com/alonsoF100/golos/internal/transport/http/dto"
 	"github.com/go-chi/chi/v5"
 )
@@ -14,55 +15,50 @@ import (
 /*
 pattern: /golos/elections
 method:  POST
-info:    JSON in request body
+info:    JSON in request body, vote_policy: final | changes_until_close (default)
 
 succeed:
   - status code:   201 created
   - response body: JSON represented created election
 
 failed:
-  - status code:   400, 409, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 409, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) CreateElection(w http.ResponseWriter, r *http.Request) {
 	var req dto.ElectionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	election, err := h.service.CreateElection(req.UserID, req.Name, req.Description)
+	election, err := h.service.CreateElection(r.Context(), RequestMetaFromRequest(r), req.UserID, req.Name, req.Description, req.VotePolicy)
 	if err != nil {
-		switch err {
-		case apperrors.ErrElectionAlreadyExist:
-			WriteJSON(w, http.StatusConflict, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, election.Version)
 	WriteJSON(w, http.StatusCreated, dto.NewElectionResponse(election))
 }
 
 /*
-pattern: /golos/elections?limit=20&offset=0&nickname=alonso
+pattern: /golos/elections?limit=20&offset=0&nickname=alonso&q=budget&status=open&created_after=2025-01-01T00:00:00Z&sort=name&order=asc
 method:  GET
-info:    query (limit, offset, nickname)
+info:    query (limit, offset, nickname, q, status, created_after, created_before, sort, order), texts translated by Accept-Language
 
 succeed:
   - status code:   200 ok
   - response body: JSON represented elections
 
 failed:
-  - status code:   500
-  - response body: JSON with error + time
+  - status code:   400, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetElections(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetElections
@@ -71,6 +67,7 @@ func (h *Handler) GetElections(w http.ResponseWriter, r *http.Request) {
 	limitStr := query.Get("limit")
 	offsetStr := query.Get("offset")
 	req.Nickname = query.Get("nickname")
+	req.ElectionFilter = readElectionFilter(query)
 	var limit, offset int
 
 	if limitStr == "" {
@@ -78,7 +75,7 @@ func (h *Handler) GetElections(w http.ResponseWriter, r *http.Request) {
 	} else {
 		limit, err = strconv.Atoi(limitStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("limit must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidLimit)
 			return
 		}
 	}
@@ -88,29 +85,96 @@ func (h *Handler) GetElections(w http.ResponseWriter, r *http.Request) {
 	} else {
 		offset, err = strconv.Atoi(offsetStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("offset must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidOffset)
 			return
 		}
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	elections, err := h.service.GetElections(limit, offset, req.Nickname)
+	elections, err := h.service.GetElections(r.Context(), limit, offset, req.Nickname, req.ToModel(), i18n.FromContext(r.Context()))
 	if err != nil {
-		WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, dto.NewElectionsResponse(elections))
 }
 
+/*
+pattern: /golos/elections/search?q=budget&status=open&created_after=2025-01-01T00:00:00Z&sort=relevance&limit=20&offset=0
+method:  GET
+info:    query (limit, offset, q, status, created_after, created_before, sort, order), nickname is not required, texts translated by Accept-Language
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented elections
+
+failed:
+  - status code:   400, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) SearchElections(w http.ResponseWriter, r *http.Request) {
+	var req dto.SearchElections
+	query := r.URL.Query()
+	var err error
+	limitStr := query.Get("limit")
+	offsetStr := query.Get("offset")
+	req.ElectionFilter = readElectionFilter(query)
+	var limit, offset int
+
+	if limitStr == "" {
+		limit = 20
+	} else {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			WriteError(w, r, apperrors.ErrInvalidLimit)
+			return
+		}
+	}
+
+	if offsetStr == "" {
+		offset = 0
+	} else {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			WriteError(w, r, apperrors.ErrInvalidOffset)
+			return
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	elections, err := h.service.SearchElections(r.Context(), limit, offset, req.ToModel(), i18n.FromContext(r.Context()))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewElectionsResponse(elections))
+}
+
+func readElectionFilter(query url.Values) dto.ElectionFilter {
+	return dto.ElectionFilter{
+		Query:         query.Get("q"),
+		Status:        query.Get("status"),
+		CreatedAfter:  query.Get("created_after"),
+		CreatedBefore: query.Get("created_before"),
+		SortBy:        query.Get("sort"),
+		SortOrder:     query.Get("order"),
+	}
+}
+
 /*
 pattern: /golos/elections/{id}
 method:  GET
-info:    UUID from pattern
+info:    UUID from pattern, texts translated by Accept-Language
 
 succeed:
   - status code:   200 ok
@@ -118,64 +182,59 @@ succeed:
 
 failed:
   - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetElection(w http.ResponseWriter, r *http.Request) {
 	var req dto.ElectionID
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	election, err := h.service.GetElection(req.ID)
+	election, err := h.service.GetElection(r.Context(), req.ID, i18n.FromContext(r.Context()))
 	if err != nil {
-		switch err {
-		case apperrors.ErrElectionNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, election.Version)
 	WriteJSON(w, http.StatusOK, dto.NewElectionResponse(election))
 }
 
 /*
 pattern: /golos/elections/{id}
 method:  DELETE
-info:    UUID from pattern
+info:    UUID from pattern + If-Match header with ETag, soft delete (restorable until purge)
 
 succeed:
   - status code:   204 no content
   - response body: -
 
 failed:
-  - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 404, 412, 428, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) DeleteElection(w http.ResponseWriter, r *http.Request) {
 	var req dto.ElectionID
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	err := h.service.DeleteElection(req.ID)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrElectionNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteElection(r.Context(), RequestMetaFromRequest(r), req.ID, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusNoContent, nil)
@@ -184,44 +243,42 @@ func (h *Handler) DeleteElection(w http.ResponseWriter, r *http.Request) {
 /*
 pattern: /golos/elections/{id}
 method:  PATCH
-info:    UUID from pattern + JSON in request body
+info:    UUID from pattern + JSON in request body + If-Match header with ETag
 
 succeed:
   - status code:   200 ok
   - response body: JSON represented updated election
 
 failed:
-  - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 404, 412, 428, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) PatchElection(w http.ResponseWriter, r *http.Request) {
 	var req dto.ElectionPatch
 	req.ID = chi.URLParam(r, "id")
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	election, err := h.service.PatchElection(req.ID, req.UserID, req.Name, req.Description)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrElectionNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		case apperrors.ErrNothingToChange:
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	election, err := h.service.PatchElection(r.Context(), RequestMetaFromRequest(r), req.ID, req.UserID, req.Name, req.Description, req.Status, req.VotePolicy, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, election.Version)
 	WriteJSON(w, http.StatusOK, dto.NewElectionResponse(election))
 }
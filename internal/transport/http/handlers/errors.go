@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/i18n"
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+	"github.com/alonsoF100/golos/internal/transport/http/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// Единая точка превращения ошибки в ответ: статус и код берутся из AppError,
+// обернутые и объединенные ошибки разбираются через errors.As
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	locale := i18n.FromContext(r.Context())
+	appErr := toAppError(err, locale)
+
+	if appErr.Status >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "Request failed", "error", err)
+	}
+	if appErr.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="golos"`)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(appErr.Status)
+	if err := json.NewEncoder(w).Encode(dto.NewProblemResponse(appErr, locale, r.URL.Path)); err != nil {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "Failed to write error response", "error", err)
+	}
+}
+
+func toAppError(err error, locale string) *apperrors.AppError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return apperrors.ErrValidation.WithFields(validation.FieldErrors(validationErrs, locale)...)
+	}
+
+	return apperrors.From(err)
+}
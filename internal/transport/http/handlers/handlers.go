@@ -1,46 +1,85 @@
 package handlers
 
 import (
+	"context"
+
+	"github.com/alonsoF100/golos/internal/health"
 	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/transport/http/validation"
 	"github.com/go-playground/validator/v10"
 )
 
 type UserService interface {
-	CreateUser(nickname, password string) (*models.User, error)
-	GetUsers(limit, offset int) ([]*models.User, error)
-	GetUser(uuid string) (*models.User, error)
-	UpdateUser(uuid, nickname, password string) (*models.User, error)
-	DeleteUser(uuid string) error
-	PatchUser(uuid string, nickname, password *string) (*models.User, error)
+	CreateUser(ctx context.Context, meta models.RequestMeta, nickname, password string) (*models.User, error)
+	GetUsers(ctx context.Context, actor *models.User, limit, offset int) ([]*models.User, error)
+	GetUser(ctx context.Context, uuid string) (*models.User, error)
+	UpdateUser(ctx context.Context, meta models.RequestMeta, uuid, nickname string, versions []int64) (*models.User, error)
+	DeleteUser(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error
+	PatchUser(ctx context.Context, meta models.RequestMeta, uuid string, nickname *string, versions []int64) (*models.User, error)
 }
 
 type ElectionService interface {
-	CreateElection(userID string, name string, description string) (*models.Election, error)
-	GetElection(uuid string) (*models.Election, error)
-	DeleteElection(uuid string) error
-	PatchElection(uuid string, userID, name, description *string) (*models.Election, error)
+	CreateElection(ctx context.Context, meta models.RequestMeta, userID string, name string, description string, votePolicy string) (*models.Election, error)
+	GetElection(ctx context.Context, uuid, locale string) (*models.Election, error)
+	DeleteElection(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error
+	PatchElection(ctx context.Context, meta models.RequestMeta, uuid string, userID, name, description, status, votePolicy *string, versions []int64) (*models.Election, error)
+	SearchElections(ctx context.Context, limit, offset int, filter models.ElectionFilter, locale string) ([]*models.Election, error)
+	SetElectionTranslation(ctx context.Context, meta models.RequestMeta, electionID, locale, name, description string) (*models.ElectionTranslation, error)
+	DeleteElectionTranslation(ctx context.Context, meta models.RequestMeta, electionID, locale string) error
+	GetElectionTranslations(ctx context.Context, electionID string) ([]*models.ElectionTranslation, error)
 }
 
 // Интерфейс для кросс-доменных операций
 type Facade interface {
-	GetElections(limit, offset int, nickname string) ([]*models.Election, error)
-	GetUserVotes(nickname, electionID string, limit int, offset int) ([]*models.Vote, error)
+	GetElections(ctx context.Context, limit, offset int, nickname string, filter models.ElectionFilter, locale string) ([]*models.Election, error)
+	GetUserVotes(ctx context.Context, nickname, electionID string, limit int, offset int) ([]*models.Vote, error)
+	DeleteVote(ctx context.Context, meta models.RequestMeta, voteID string) error
+	PatchVote(ctx context.Context, meta models.RequestMeta, voteID string, userID, voteVariantID *string) (*models.Vote, error)
 }
 
 type VoteVariantService interface {
-	CreateVoteVariant(electionID, name string) (*models.VoteVariant, error)
-	GetVoteVariants(electionID string) ([]*models.VoteVariant, error)
-	GetVoteVariant(uuid string) (*models.VoteVariant, error)
-	DeleteVoteVariant(uuid string) error
-	UpdateVoteVariant(uuid string, name string) (*models.VoteVariant, error)
+	CreateVoteVariant(ctx context.Context, meta models.RequestMeta, electionID, name string) (*models.VoteVariant, error)
+	GetVoteVariants(ctx context.Context, electionID, locale string) ([]*models.VoteVariant, error)
+	GetVoteVariant(ctx context.Context, uuid, locale string) (*models.VoteVariant, error)
+	DeleteVoteVariant(ctx context.Context, meta models.RequestMeta, uuid string, versions []int64) error
+	UpdateVoteVariant(ctx context.Context, meta models.RequestMeta, uuid string, name string, versions []int64) (*models.VoteVariant, error)
+	SetVoteVariantTranslation(ctx context.Context, meta models.RequestMeta, voteVariantID, locale, name string) (*models.VoteVariantTranslation, error)
+	DeleteVoteVariantTranslation(ctx context.Context, meta models.RequestMeta, voteVariantID, locale string) error
+	GetVoteVariantTranslations(ctx context.Context, voteVariantID string) ([]*models.VoteVariantTranslation, error)
 }
 
 type VoteService interface {
-	CreateVote(userID, voteVariantID string) (*models.Vote, error)
-	GetVote(voteID string) (*models.Vote, error)
-	GetVariantVotes(voteVariantID string) ([]*models.Vote, error)
-	DeleteVote(voteID string) error
-	PatchVote(voteID string, userID, voteVariantID *string) (*models.Vote, error)
+	CreateVote(ctx context.Context, meta models.RequestMeta, userID, voteVariantID string) (*models.Vote, error)
+	GetVote(ctx context.Context, voteID string) (*models.Vote, error)
+	GetVariantVotes(ctx context.Context, voteVariantID string) ([]*models.Vote, error)
+	GetVoteHistory(ctx context.Context, voteID string) ([]*models.VoteChange, error)
+}
+
+type AuthService interface {
+	Authenticate(ctx context.Context, nickname, password, ip string) (*models.User, error)
+	ChangePassword(ctx context.Context, meta models.RequestMeta, uuid, currentPassword, newPassword, ip string) error
+	GetUserRole(ctx context.Context, userID string) (string, error)
+}
+
+// Административные операции, роль проверяется в сервисе
+type AdminService interface {
+	SetUserRole(ctx context.Context, meta models.RequestMeta, userID, role string) (*models.User, error)
+	CloseElection(ctx context.Context, meta models.RequestMeta, electionID string) (*models.Election, error)
+	RemoveVote(ctx context.Context, meta models.RequestMeta, voteID string) error
+	RestoreUser(ctx context.Context, meta models.RequestMeta, userID string) (*models.User, error)
+	RestoreElection(ctx context.Context, meta models.RequestMeta, electionID string) (*models.Election, error)
+	RestoreVoteVariant(ctx context.Context, meta models.RequestMeta, voteVariantID string) (*models.VoteVariant, error)
+	PurgeDeleted(ctx context.Context, meta models.RequestMeta) (*models.PurgeResult, error)
+}
+
+type AuditService interface {
+	GetAuditEvents(ctx context.Context, actor *models.User, limit, offset int, filter models.AuditFilter) ([]*models.AuditEvent, error)
+}
+
+type IdempotencyService interface {
+	BeginIdempotentRequest(ctx context.Context, key, requestHash string) (*models.IdempotencyRecord, error)
+	CompleteIdempotentRequest(ctx context.Context, key string, statusCode int, etag, location string, body []byte) error
+	ReleaseIdempotentRequest(ctx context.Context, key string) error
 }
 
 type Service interface {
@@ -49,16 +88,22 @@ type Service interface {
 	Facade
 	VoteVariantService
 	VoteService
+	AuthService
+	AdminService
+	IdempotencyService
+	AuditService
 }
 
 type Handler struct {
 	service   Service
+	health    *health.Checker
 	validator *validator.Validate
 }
 
-func New(service Service) *Handler {
+func New(service Service, health *health.Checker) *Handler {
 	return &Handler{
 		service:   service,
-		validator: validator.New(),
+		health:    health,
+		validator: validation.New(),
 	}
 }
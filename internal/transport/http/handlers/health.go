@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alonsoF100/golos/internal/health"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+)
+
+/*
+pattern: /healthz
+method:  GET
+info:    liveness, the process is running and serving requests
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON {"status": "up"}
+*/
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, dto.HealthResponse{Status: health.StatusUp})
+}
+
+/*
+pattern: /readyz
+method:  GET
+info:    readiness, checks postgres, schema version and redis (when configured); not ready during shutdown
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON status with a breakdown per dependency
+
+failed:
+  - status code:   503 service unavailable
+  - response body: JSON status with a breakdown per dependency
+*/
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ready, results := h.health.Check(r.Context())
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	WriteJSON(w, status, dto.NewHealthResponse(ready, results))
+}
@@ -3,8 +3,15 @@ package handlers
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -14,3 +21,53 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 		fmt.Printf("error: %v, time: %v\n", err.Error(), time.Now())
 	}
 }
+
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// Автор изменения и ID запроса для журнала изменений
+func RequestMetaFromRequest(r *http.Request) models.RequestMeta {
+	return models.RequestMeta{
+		Actor:     UserFromContext(r.Context()),
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// ETag ресурса - его версия, If-Match должен вернуть ее без изменений
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// nil - If-Match: * (изменение без проверки версии). Список ETag через запятую
+// (RFC 9110) разрешает изменение, если текущая версия совпадает с любой из них
+func ParseIfMatch(r *http.Request) ([]int64, error) {
+	ifMatch := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if ifMatch == "" {
+		return nil, apperrors.ErrPreconditionRequired
+	}
+	if ifMatch == "*" {
+		return nil, nil
+	}
+
+	var versions []int64
+	for _, tag := range strings.Split(ifMatch, ",") {
+		// Слабые ETag принимаются: версия одна и та же для всех представлений ресурса
+		unquoted, err := strconv.Unquote(strings.TrimPrefix(strings.TrimSpace(tag), "W/"))
+		if err != nil {
+			return nil, apperrors.ErrInvalidIfMatch
+		}
+		version, err := strconv.ParseInt(unquoted, 10, 64)
+		if err != nil {
+			return nil, apperrors.ErrInvalidIfMatch
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		versions []int64
+		err      error
+	}{
+		{name: "missing", err: apperrors.ErrPreconditionRequired},
+		{name: "any", header: []string{"*"}},
+		{name: "single", header: []string{`"3"`}, versions: []int64{3}},
+		{name: "weak", header: []string{`W/"3"`}, versions: []int64{3}},
+		{name: "list", header: []string{`"3", W/"4" ,"5"`}, versions: []int64{3, 4, 5}},
+		{name: "repeated header", header: []string{`"3"`, `"4"`}, versions: []int64{3, 4}},
+		{name: "unquoted", header: []string{"3"}, err: apperrors.ErrInvalidIfMatch},
+		{name: "not a version", header: []string{`"abc"`}, err: apperrors.ErrInvalidIfMatch},
+		{name: "any in list", header: []string{`*, "3"`}, err: apperrors.ErrInvalidIfMatch},
+		{name: "empty element", header: []string{`"3",`}, err: apperrors.ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/", nil)
+			for _, value := range tt.header {
+				r.Header.Add("If-Match", value)
+			}
+
+			versions, err := ParseIfMatch(r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Fatalf("expected versions %v, got %v", tt.versions, versions)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/logger"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// Запоминает статус и тело ответа, продолжая писать их клиенту
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+/*
+middleware: Idempotency-Key for POST requests
+info:       retry with the same key and body returns the original status, ETag, Location and body
+info:       the key is scoped by the authenticated user (client IP for anonymous requests) and the request path
+
+failed:
+  - status code:   400 invalid key, 409 first request still in progress, 422 key reused with a different request
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) Idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || idempotencyKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			WriteError(w, r, apperrors.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Анонимные клиенты не должны получать чужие ответы по совпавшему ключу
+		scope := "anonymous:" + ClientIP(r)
+		if user := UserFromContext(r.Context()); user != nil {
+			scope = user.ID
+		}
+		key := scope + ":" + r.URL.Path + ":" + idempotencyKey
+
+		hash := sha256.New()
+		hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		record, err := h.service.BeginIdempotentRequest(r.Context(), key, requestHash)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+
+		if record != nil {
+			w.Header().Set(IdempotencyReplayedHeader, "true")
+			if record.ETag != "" {
+				w.Header().Set("ETag", record.ETag)
+			}
+			if record.Location != "" {
+				w.Header().Set("Location", record.Location)
+			}
+			if len(record.Body) > 0 && record.StatusCode >= http.StatusBadRequest {
+				w.Header().Set("Content-Type", "application/problem+json")
+			} else if len(record.Body) > 0 {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			w.WriteHeader(record.StatusCode)
+			w.Write(record.Body)
+			return
+		}
+
+		// Паника обработчика не должна оставить ключ занятым до истечения ttl
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				h.releaseIdempotencyKey(r, key)
+				panic(rvr)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		// Временные ошибки не запоминаются, повтор должен выполниться заново
+		if rec.status >= http.StatusInternalServerError || rec.status == http.StatusTooManyRequests {
+			h.releaseIdempotencyKey(r, key)
+			return
+		}
+
+		etag, location := rec.Header().Get("ETag"), rec.Header().Get("Location")
+		if err := h.service.CompleteIdempotentRequest(context.WithoutCancel(r.Context()), key, rec.status, etag, location, rec.body.Bytes()); err != nil {
+			logger.FromContext(r.Context()).ErrorContext(r.Context(), "Failed to save idempotent response", "error", err, "key", key)
+		}
+	})
+}
+
+func (h *Handler) releaseIdempotencyKey(r *http.Request, key string) {
+	if err := h.service.ReleaseIdempotentRequest(context.WithoutCancel(r.Context()), key); err != nil {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "Failed to release idempotency key", "error", err, "key", key)
+	}
+}
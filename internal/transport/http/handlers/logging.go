@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+/*
+middleware: assigns X-Request-ID or keeps the one sent by the client
+info:       the id is returned in X-Request-ID and stored in the audit log
+*/
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		// Ключ chi, чтобы middleware.GetReqID продолжал работать
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Чужой id попадает в логи и журнал, поэтому только печатные ascii символы и ограниченная длина
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+middleware: request-scoped logger and access log
+info:       one line per request with method, route, status, latency and bytes
+*/
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestLogger := slog.Default().With(
+			"request_id", middleware.GetReqID(r.Context()),
+			"method", r.Method,
+			"path", r.URL.Path,
+		)
+		ctx := logger.WithContext(r.Context(), requestLogger)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		var route string
+		if rctx := chi.RouteContext(ctx); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		requestLogger.Log(ctx, level, "Request completed",
+			"route", route,
+			"status", status,
+			"latency", time.Since(start),
+			"bytes", ww.BytesWritten(),
+			"remote_ip", ClientIP(r))
+	})
+}
+
+/*
+middleware: turns a panic in a handler into 500
+
+failed:
+  - status code:   500 internal server error
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+			// Обрыв ответа по инициативе обработчика должен дойти до net/http
+			if rvr == http.ErrAbortHandler {
+				panic(rvr)
+			}
+
+			logger.FromContext(r.Context()).ErrorContext(r.Context(), "Handler panicked",
+				"panic", rvr,
+				"stack", string(debug.Stack()))
+			WriteError(w, r, apperrors.ErrInternal)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/transport/http/dto"
+	"github.com/go-chi/chi/v5"
+)
+
+/*
+pattern: /golos/elections/{id}/translations
+method:  GET
+info:    UUID from pattern
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented election translations for all locales
+
+failed:
+  - status code:   400, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) GetElectionTranslations(w http.ResponseWriter, r *http.Request) {
+	var req dto.ElectionID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	translations, err := h.service.GetElectionTranslations(r.Context(), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewElectionTranslationsResponse(translations))
+}
+
+/*
+pattern: /golos/elections/{id}/translations/{locale}
+method:  PUT
+info:    UUID and locale (en, ru) from pattern + JSON in request body ({"name": "...", "description": "..."})
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented election translation
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) SetElectionTranslation(w http.ResponseWriter, r *http.Request) {
+	var req dto.ElectionTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
+		return
+	}
+	req.ID = chi.URLParam(r, "id")
+	req.Locale = chi.URLParam(r, "locale")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	translation, err := h.service.SetElectionTranslation(r.Context(), RequestMetaFromRequest(r), req.ID, req.Locale, req.Name, req.Description)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewElectionTranslationResponse(translation))
+}
+
+/*
+pattern: /golos/elections/{id}/translations/{locale}
+method:  DELETE
+info:    UUID and locale (en, ru) from pattern
+
+succeed:
+  - status code:   204 no content
+  - response body: -
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) DeleteElectionTranslation(w http.ResponseWriter, r *http.Request) {
+	var req dto.ElectionTranslationID
+	req.ID = chi.URLParam(r, "id")
+	req.Locale = chi.URLParam(r, "locale")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err := h.service.DeleteElectionTranslation(r.Context(), RequestMetaFromRequest(r), req.ID, req.Locale)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusNoContent, nil)
+}
+
+/*
+pattern: /golos/vote_variants/{id}/translations
+method:  GET
+info:    UUID from pattern
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented vote variant translations for all locales
+
+failed:
+  - status code:   400, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) GetVoteVariantTranslations(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteVariantID
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	translations, err := h.service.GetVoteVariantTranslations(r.Context(), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewVoteVariantTranslationsResponse(translations))
+}
+
+/*
+pattern: /golos/vote_variants/{id}/translations/{locale}
+method:  PUT
+info:    UUID and locale (en, ru) from pattern + JSON in request body ({"name": "..."})
+
+succeed:
+  - status code:   200 ok
+  - response body: JSON represented vote variant translation
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) SetVoteVariantTranslation(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteVariantTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
+		return
+	}
+	req.ID = chi.URLParam(r, "id")
+	req.Locale = chi.URLParam(r, "locale")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	translation, err := h.service.SetVoteVariantTranslation(r.Context(), RequestMetaFromRequest(r), req.ID, req.Locale, req.Name)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewVoteVariantTranslationResponse(translation))
+}
+
+/*
+pattern: /golos/vote_variants/{id}/translations/{locale}
+method:  DELETE
+info:    UUID and locale (en, ru) from pattern
+
+succeed:
+  - status code:   204 no content
+  - response body: -
+
+failed:
+  - status code:   400, 401, 403, 404, 500
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) DeleteVoteVariantTranslation(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteVariantTranslationID
+	req.ID = chi.URLParam(r, "id")
+	req.Locale = chi.URLParam(r, "locale")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err := h.service.DeleteVoteVariantTranslation(r.Context(), RequestMetaFromRequest(r), req.ID, req.Locale)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusNoContent, nil)
+}
@@ -2,11 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/models"
 	"github.com/alonsoF100/golos/internal/transport/http/dto"
 	"github.com/go-chi/chi/v5"
 )
@@ -24,39 +24,34 @@ succeed:
 failed:
 
 	-status code:   400, 409, 500
-	-response body: JSON with error + time
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.UserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	user, err := h.service.CreateUser(req.Nickname, req.Password)
+	user, err := h.service.CreateUser(r.Context(), RequestMetaFromRequest(r), req.Nickname, req.Password)
 	if err != nil {
-		switch err {
-		case apperrors.ErrUserAlreadyExist:
-			WriteJSON(w, http.StatusConflict, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
-	WriteJSON(w, http.StatusCreated, dto.NewUserResponse(user))
+	SetETag(w, user.Version)
+	WriteJSON(w, http.StatusCreated, dto.NewUserSelfResponse(user))
 }
 
 /*
 pattern: /golos/users?limit=20&offset=20
 method:  GET
-info:    query (limit, offset)
+info:    query (limit, offset), moderator or admin only
 
 succeed:
 
@@ -65,8 +60,8 @@ succeed:
 
 failed:
 
-	-status code:   500, 400
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
@@ -80,7 +75,7 @@ func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	} else {
 		limit, err = strconv.Atoi(limitStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("limit must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidLimit)
 			return
 		}
 	}
@@ -90,18 +85,15 @@ func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	} else {
 		offset, err = strconv.Atoi(offsetStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("offset must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidOffset)
 			return
 		}
 	}
 
-	users, err := h.service.GetUsers(limit, offset)
+	users, err := h.service.GetUsers(r.Context(), UserFromContext(r.Context()), limit, offset)
 	if err != nil {
-		switch err {
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusOK, dto.NewUsersResponse(users))
@@ -115,12 +107,12 @@ info:    UUID from pattern
 succeed:
 
 	-status code:   200 ok
-	-response body: JSON represented user
+	-response body: JSON represented user (self view for the user himself, moderators and admins)
 
 failed:
 
 	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.UserID
@@ -128,29 +120,43 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	user, err := h.service.GetUser(req.ID)
+	user, err := h.service.GetUser(r.Context(), req.ID)
 	if err != nil {
-		switch err {
-		case apperrors.ErrUserNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
+		return
+	}
+
+	actor := UserFromContext(r.Context())
+	if !canSeeSelfView(actor, user.ID) {
+		SetETag(w, user.Version)
+		WriteJSON(w, http.StatusOK, dto.NewUserResponse(user))
+		return
+	}
+
+	// Модератору и администратору роль чужого пользователя загружается отдельно
+	if actor.ID == user.ID {
+		user.Role = actor.Role
+	} else {
+		role, err := h.service.GetUserRole(r.Context(), user.ID)
+		if err != nil {
+			WriteError(w, r, err)
 			return
 		}
+		user.Role = role
 	}
 
-	WriteJSON(w, http.StatusOK, dto.NewUserResponse(user))
+	SetETag(w, user.Version)
+	WriteJSON(w, http.StatusOK, dto.NewUserSelfResponse(user))
 }
 
 /*
 pattern: /golos/users/{id}
 method:  PUT
-info:    UUID from pattern + JSON in request body
+info:    UUID from pattern + If-Match header with ETag + JSON in request body (nickname), password is changed via /golos/users/{id}/password
 
 succeed:
 
@@ -159,8 +165,8 @@ succeed:
 
 failed:
 
-	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 409, 412, 428, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.UserUpdate
@@ -168,34 +174,35 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	user, err := h.service.UpdateUser(req.ID, req.Nickname, req.Password)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrUserNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	user, err := h.service.UpdateUser(r.Context(), RequestMetaFromRequest(r), req.ID, req.Nickname, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
 	}
 
-	WriteJSON(w, http.StatusOK, user)
+	SetETag(w, user.Version)
+	WriteJSON(w, http.StatusOK, dto.NewUserSelfResponse(user))
 }
 
 /*
 pattern: /golos/users/{id}
 method:  DELETE
-info:    UUID from pattern
+info:    UUID from pattern + If-Match header with ETag, soft delete (restorable until purge)
 
 succeed:
 
@@ -204,8 +211,8 @@ succeed:
 
 failed:
 
-	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 412, 428, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.UserID
@@ -213,20 +220,20 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	err := h.service.DeleteUser(req.ID)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrUserNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	err = h.service.DeleteUser(r.Context(), RequestMetaFromRequest(r), req.ID, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusNoContent, nil)
@@ -235,7 +242,7 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 /*
 pattern: /golos/users/{id}
 method:  PATCH
-info:    UUID from pattern + JSON in request body
+info:    UUID from pattern + If-Match header with ETag + JSON in request body (nickname), password is changed via /golos/users/{id}/password
 
 succeed:
 
@@ -244,8 +251,8 @@ succeed:
 
 failed:
 
-	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 409, 412, 428, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) PatchUser(w http.ResponseWriter, r *http.Request) {
 	var req dto.UserPatch
@@ -253,26 +260,74 @@ func (h *Handler) PatchUser(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	user, err := h.service.PatchUser(req.ID, req.Nickname, req.Password)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrUserNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	user, err := h.service.PatchUser(r.Context(), RequestMetaFromRequest(r), req.ID, req.Nickname, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	SetETag(w, user.Version)
+	WriteJSON(w, http.StatusOK, dto.NewUserSelfResponse(user))
+}
+
+/*
+pattern: /golos/users/{id}/password
+method:  POST
+info:    UUID from pattern + JSON in request body (current_password, new_password)
+
+succeed:
+
+	-status code:   204 no content
+	-response body: -
+
+failed:
+
+	-status code:   400, 403, 404, 423, 429, 500
+	-response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserPasswordChange
+
+	req.ID = chi.URLParam(r, "id")
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	err := h.service.ChangePassword(r.Context(), RequestMetaFromRequest(r), req.ID, req.CurrentPassword, req.NewPassword, ClientIP(r))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusNoContent, nil)
+}
+
+func canSeeSelfView(actor *models.User, userID string) bool {
+	if actor == nil {
+		return false
 	}
 
-	WriteJSON(w, http.StatusOK, dto.NewUserResponse(user))
+	return actor.ID == userID || actor.Role == models.RoleModerator || actor.Role == models.RoleAdmin
 }
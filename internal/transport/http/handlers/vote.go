@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -23,31 +22,25 @@ succeed:
 
 failed:
 
-	-status code:   400, 409, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 409, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) CreateVote(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	vote, err := h.service.CreateVote(req.UserID, req.VariantID)
+	vote, err := h.service.CreateVote(r.Context(), RequestMetaFromRequest(r), req.UserID, req.VariantID)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteAlreadyExist:
-			WriteJSON(w, http.StatusConflict, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusCreated, dto.NewVoteResponse(vote))
@@ -66,7 +59,7 @@ succeed:
 failed:
 
 	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetVote(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteID
@@ -74,20 +67,14 @@ func (h *Handler) GetVote(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	vote, err := h.service.GetVote(req.ID)
+	vote, err := h.service.GetVote(r.Context(), req.ID)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusOK, dto.NewVoteResponse(vote))
@@ -105,8 +92,8 @@ succeed:
 
 failed:
 
-	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 409, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) DeleteVote(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteID
@@ -114,20 +101,14 @@ func (h *Handler) DeleteVote(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	err := h.service.DeleteVote(req.ID)
+	err := h.service.DeleteVote(r.Context(), RequestMetaFromRequest(r), req.ID)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusNoContent, nil)
@@ -145,8 +126,8 @@ succeed:
 
 failed:
 
-	-status code:   400, 404, 500
-	-response body: JSON with error + time
+	-status code:   400, 401, 403, 404, 409, 500
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) PatchVote(w http.ResponseWriter, r *http.Request) {
 	var req dto.VotePatch
@@ -154,31 +135,19 @@ func (h *Handler) PatchVote(w http.ResponseWriter, r *http.Request) {
 	req.ID = chi.URLParam(r, "id")
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	vote, err := h.service.PatchVote(req.ID, &req.UserID, &req.VariantID)
+	vote, err := h.service.PatchVote(r.Context(), RequestMetaFromRequest(r), req.ID, req.UserID, req.VariantID)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		case apperrors.ErrUserNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		case apperrors.ErrElectionNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusOK, dto.NewVoteResponse(vote))
@@ -197,7 +166,7 @@ succeed:
 failed:
 
 	-status code:   500, 400
-	-response body: JSON with error + time
+	-response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetUserVotes(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetUserVotes
@@ -214,7 +183,7 @@ func (h *Handler) GetUserVotes(w http.ResponseWriter, r *http.Request) {
 	} else {
 		limit, err = strconv.Atoi(limitStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("limit must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidLimit)
 			return
 		}
 	}
@@ -224,24 +193,55 @@ func (h *Handler) GetUserVotes(w http.ResponseWriter, r *http.Request) {
 	} else {
 		offset, err = strconv.Atoi(offsetStr)
 		if err != nil {
-			WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(fmt.Errorf("offset must be a number")))
+			WriteError(w, r, apperrors.ErrInvalidOffset)
 			return
 		}
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	votes, err := h.service.GetUserVotes(req.Nickname, req.ElectionID, limit, offset)
+	votes, err := h.service.GetUserVotes(r.Context(), req.Nickname, req.ElectionID, limit, offset)
 	if err != nil {
-		switch err {
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusOK, dto.NewVotesResponse(votes))
 }
+
+/*
+pattern: /golos/votes/{id}/history
+method:  GET
+info:    UUID from pattern
+
+succeed:
+
+	-status code:   200 ok
+	-response body: JSON represented vote changes, oldest first (first entry is the original vote, an entry without new_variant_id is a withdrawal)
+
+failed:
+
+	-status code:   400, 404, 500
+	-response body: problem+json (RFC 7807) with code and detail
+*/
+func (h *Handler) GetVoteHistory(w http.ResponseWriter, r *http.Request) {
+	var req dto.VoteID
+
+	req.ID = chi.URLParam(r, "id")
+
+	if err := h.validator.Struct(req); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	history, err := h.service.GetVoteHistory(r.Context(), req.ID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.NewVoteHistoryResponse(req.ID, history))
+}
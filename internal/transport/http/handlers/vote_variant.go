@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/i18n"
 	"github.com/alonsoF100/golos/internal/transport/http/dto"
 	"github.com/go-chi/chi/v5"
 )
@@ -19,40 +20,35 @@ succeed:
   - response body: JSON represented created vote variant
 
 failed:
-  - status code:   400, 409, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 404, 409, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) CreateVoteVariant(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteVariantRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	voteVariant, err := h.service.CreateVoteVariant(req.ElectionID, req.Name)
+	voteVariant, err := h.service.CreateVoteVariant(r.Context(), RequestMetaFromRequest(r), req.ElectionID, req.Name)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteVariantAlreadyExist:
-			WriteJSON(w, http.StatusConflict, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, voteVariant.Version)
 	WriteJSON(w, http.StatusCreated, dto.NewVoteVariantResponse(voteVariant))
 }
 
 /*
 pattern: /golos/vote-variants?election_id=elelnslksvmnspvmopsevmpoesvm
 method:  GET
-info:    electionID from query
+info:    electionID from query, names translated by Accept-Language
 
 succeed:
   - status code:   200 ok
@@ -60,7 +56,7 @@ succeed:
 
 failed:
   - status code:   500
-  - response body: JSON with error + time
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetVoteVariants(w http.ResponseWriter, r *http.Request) {
 	var req dto.GetVoteVariantsRequest
@@ -68,13 +64,13 @@ func (h *Handler) GetVoteVariants(w http.ResponseWriter, r *http.Request) {
 	req.ElectionID = query.Get("election_id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	voteVariants, err := h.service.GetVoteVariants(req.ElectionID)
+	voteVariants, err := h.service.GetVoteVariants(r.Context(), req.ElectionID, i18n.FromContext(r.Context()))
 	if err != nil {
-		WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
@@ -84,7 +80,7 @@ func (h *Handler) GetVoteVariants(w http.ResponseWriter, r *http.Request) {
 /*
 pattern: /golos/vote_variants/{id}
 method:  GET
-info:    UUID from pattern
+info:    UUID from pattern, name translated by Accept-Language
 
 succeed:
   - status code:   200 ok
@@ -92,64 +88,59 @@ succeed:
 
 failed:
   - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) GetVoteVariant(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteVariantID
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	voteVariant, err := h.service.GetVoteVariant(req.ID)
+	voteVariant, err := h.service.GetVoteVariant(r.Context(), req.ID, i18n.FromContext(r.Context()))
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteVariantNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, voteVariant.Version)
 	WriteJSON(w, http.StatusOK, dto.NewVoteVariantResponse(voteVariant))
 }
 
 /*
 pattern: /golos/vote_variants/{id}
 method:  DELETE
-info:    UUID from pattern
+info:    UUID from pattern + If-Match header with ETag, soft delete (restorable until purge)
 
 succeed:
   - status code:   204 no content
   - response body: -
 
 failed:
-  - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 404, 412, 428, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) DeleteVoteVariant(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteVariantID
 	req.ID = chi.URLParam(r, "id")
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
+		return
+	}
+
+	versions, err := ParseIfMatch(r)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
 
-	err := h.service.DeleteVoteVariant(req.ID)
+	err = h.service.DeleteVoteVariant(r.Context(), RequestMetaFromRequest(r), req.ID, versions)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteVariantNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
 	}
 
 	WriteJSON(w, http.StatusNoContent, nil)
@@ -158,41 +149,42 @@ func (h *Handler) DeleteVoteVariant(w http.ResponseWriter, r *http.Request) {
 /*
 pattern: /golos/vote_variants/{id}
 method:  PUT
-info:    UUID from pattern + JSON in request body
+info:    UUID from pattern + JSON in request body + If-Match header with ETag
 
 succeed:
   - status code:   200 ok
   - response body: JSON represented updated vote variant
 
 failed:
-  - status code:   400, 404, 500
-  - response body: JSON with error + time
+  - status code:   400, 401, 403, 404, 412, 428, 500
+  - response body: problem+json (RFC 7807) with code and detail
 */
 func (h *Handler) UpdateVoteVariant(w http.ResponseWriter, r *http.Request) {
 	var req dto.VoteVariantUpdate
 	req.ID = chi.URLParam(r, "id")
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, apperrors.ErrInvalidBody.WithCause(err))
 		return
 	}
 
 	if err := h.validator.Struct(req); err != nil {
-		WriteJSON(w, http.StatusBadRequest, dto.NewErrorResponse(err))
+		WriteError(w, r, err)
 		return
 	}
 
-	voteVariant, err := h.service.UpdateVoteVariant(req.ID, req.Name)
+	versions, err := ParseIfMatch(r)
 	if err != nil {
-		switch err {
-		case apperrors.ErrVoteVariantNotFound:
-			WriteJSON(w, http.StatusNotFound, dto.NewErrorResponse(err))
-			return
-		default:
-			WriteJSON(w, http.StatusInternalServerError, dto.NewErrorResponse(err))
-			return
-		}
+		WriteError(w, r, err)
+		return
+	}
+
+	voteVariant, err := h.service.UpdateVoteVariant(r.Context(), RequestMetaFromRequest(r), req.ID, req.Name, versions)
+	if err != nil {
+		WriteError(w, r, err)
+		return
 	}
 
+	SetETag(w, voteVariant.Version)
 	WriteJSON(w, http.StatusOK, dto.NewVoteVariantResponse(voteVariant))
 }
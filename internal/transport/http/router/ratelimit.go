@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alonsoF100/golos/internal/config"
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/logger"
+	"github.com/alonsoF100/golos/internal/transport/http/handlers"
+)
+
+// Хранилище бакетов: в памяти процесса или в redis для нескольких реплик
+type RateLimitStore interface {
+	TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+type RateLimiter struct {
+	store RateLimitStore
+	cfg   config.RateLimitConfig
+}
+
+func NewRateLimiter(store RateLimitStore, cfg config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		store: store,
+		cfg:   cfg,
+	}
+}
+
+/*
+middleware: token bucket per route group
+info:       key is the authenticated user id or the client ip
+
+failed:
+  - status code:   429 too many requests + Retry-After header
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (l *RateLimiter) Limit(group string, rule config.RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !l.cfg.Enabled || rule.Rate <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := group + ":ip:" + handlers.ClientIP(r)
+			if user := handlers.UserFromContext(r.Context()); user != nil {
+				key = group + ":user:" + user.ID
+			}
+
+			allowed, retryAfter, err := l.store.TakeToken(r.Context(), key, rule.Rate, rule.Burst, time.Now())
+			if err != nil {
+				// Недоступное хранилище не должно ронять API
+				logger.FromContext(r.Context()).ErrorContext(r.Context(), "Rate limit store failed", "error", err, "key", key)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				handlers.WriteError(w, r, apperrors.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/*
+middleware: token bucket per client ip for requests with Basic credentials
+info:       runs before Authenticate, so bcrypt is not reachable past the limit
+
+failed:
+  - status code:   429 too many requests + Retry-After header
+  - response body: problem+json (RFC 7807) with code and detail
+*/
+func (l *RateLimiter) LimitCredentials(rule config.RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := l.Limit("credentials", rule)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, _, ok := r.BasicAuth(); !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Пользователь еще не аутентифицирован, поэтому ключ - ip
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+const memoryBucketsSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  int
+}
+
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryRateLimitStore) TakeToken(_ context.Context, key string, rate float64, burst int, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), last: now, rate: rate, burst: burst}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		retryAfter := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+		return false, retryAfter, nil
+	}
+	bucket.tokens--
+
+	return true, 0, nil
+}
+
+// Полностью восстановившиеся бакеты ничем не отличаются от новых, их можно удалить
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryBucketsSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		refill := time.Duration(float64(bucket.burst) / bucket.rate * float64(time.Second))
+		if now.Sub(bucket.last) >= refill {
+			delete(s.buckets, key)
+		}
+	}
+}
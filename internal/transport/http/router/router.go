@@ -1,63 +1,125 @@
 package router
 
 import (
+	"net/http"
+
+	"github.com/alonsoF100/golos/internal/config"
+	"github.com/alonsoF100/golos/internal/metrics"
+	"github.com/alonsoF100/golos/internal/models"
+	"github.com/alonsoF100/golos/internal/tracing"
 	"github.com/alonsoF100/golos/internal/transport/http/handlers"
 	"github.com/go-chi/chi/v5"
 )
 
 type Router struct {
 	handlers *handlers.Handler
+	limiter  *RateLimiter
+	metrics  config.MetricsConfig
 }
 
-func New(handlers *handlers.Handler) *Router {
+func New(handlers *handlers.Handler, limiter *RateLimiter, metrics config.MetricsConfig) *Router {
 	return &Router{
 		handlers: handlers,
+		limiter:  limiter,
+		metrics:  metrics,
 	}
 }
 
 func (rt Router) Setup() *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(handlers.RequestID)
+	r.Use(tracing.Middleware)
+	if rt.metrics.Enabled {
+		r.Use(metrics.Middleware)
+	}
+	r.Use(handlers.AccessLog)
+	r.Use(handlers.Locale)
+	r.Use(handlers.Recoverer)
+	r.Use(rt.limiter.LimitCredentials(rt.limiter.cfg.Credentials))
+	r.Use(rt.handlers.Authenticate)
+	r.Use(rt.handlers.Idempotency)
+
+	// Группы лимитов: вход и регистрация, голосование, прочие изменения, чтение
+	auth := rt.limiter.Limit("auth", rt.limiter.cfg.Auth)
+	voting := rt.limiter.Limit("voting", rt.limiter.cfg.Voting)
+	writes := rt.limiter.Limit("writes", rt.limiter.cfg.Writes)
+	reads := rt.limiter.Limit("reads", rt.limiter.cfg.Reads)
 
 	r.Route("/golos/users", func(r chi.Router) {
-		r.Post("/", rt.handlers.CreateUser)
-		r.Get("/", rt.handlers.GetUsers)
+		r.With(auth).Post("/", rt.handlers.CreateUser)
+		r.With(reads, rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin)).Get("/", rt.handlers.GetUsers)
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", rt.handlers.GetUser)
-			r.Put("/", rt.handlers.UpdateUser)
-			r.Patch("/", rt.handlers.PatchUser)
-			r.Delete("/", rt.handlers.DeleteUser)
+			r.With(reads).Get("/", rt.handlers.GetUser)
+			r.With(writes, rt.handlers.RequireAuth).Put("/", rt.handlers.UpdateUser)
+			r.With(writes, rt.handlers.RequireAuth).Patch("/", rt.handlers.PatchUser)
+			r.With(writes, rt.handlers.RequireAuth).Delete("/", rt.handlers.DeleteUser)
+			r.With(auth).Post("/password", rt.handlers.ChangePassword)
+			r.With(writes, rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin)).Post("/restore", rt.handlers.RestoreUser)
 		})
 	})
 
 	r.Route("/golos/elections", func(r chi.Router) {
-		r.Post("/", rt.handlers.CreateElection)
-		r.Get("/", rt.handlers.GetElections)
+		r.With(writes, rt.handlers.RequireAuth).Post("/", rt.handlers.CreateElection)
+		r.With(reads).Get("/", rt.handlers.GetElections)
+		r.With(reads).Get("/search", rt.handlers.SearchElections)
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", rt.handlers.GetElection)
-			r.Patch("/", rt.handlers.PatchElection)
-			r.Delete("/", rt.handlers.DeleteElection)
+			r.With(reads).Get("/", rt.handlers.GetElection)
+			r.With(writes, rt.handlers.RequireAuth).Patch("/", rt.handlers.PatchElection)
+			r.With(writes, rt.handlers.RequireAuth).Delete("/", rt.handlers.DeleteElection)
+			r.With(writes, rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin)).Post("/restore", rt.handlers.RestoreElection)
+			r.With(reads).Get("/translations", rt.handlers.GetElectionTranslations)
+			r.With(writes, rt.handlers.RequireAuth).Put("/translations/{locale}", rt.handlers.SetElectionTranslation)
+			r.With(writes, rt.handlers.RequireAuth).Delete("/translations/{locale}", rt.handlers.DeleteElectionTranslation)
 		})
 	})
 
 	r.Route("/golos/vote_variants", func(r chi.Router) {
-		r.Post("/", rt.handlers.CreateVoteVariant)
-		r.Get("/", rt.handlers.GetVoteVariants)
+		r.With(writes, rt.handlers.RequireAuth).Post("/", rt.handlers.CreateVoteVariant)
+		r.With(reads).Get("/", rt.handlers.GetVoteVariants)
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", rt.handlers.GetVoteVariant)
-			r.Put("/", rt.handlers.UpdateVoteVariant)
-			r.Delete("/", rt.handlers.DeleteVoteVariant)
+			r.With(reads).Get("/", rt.handlers.GetVoteVariant)
+			r.With(writes, rt.handlers.RequireAuth).Put("/", rt.handlers.UpdateVoteVariant)
+			r.With(writes, rt.handlers.RequireAuth).Delete("/", rt.handlers.DeleteVoteVariant)
+			r.With(writes, rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin)).Post("/restore", rt.handlers.RestoreVoteVariant)
+			r.With(reads).Get("/translations", rt.handlers.GetVoteVariantTranslations)
+			r.With(writes, rt.handlers.RequireAuth).Put("/translations/{locale}", rt.handlers.SetVoteVariantTranslation)
+			r.With(writes, rt.handlers.RequireAuth).Delete("/translations/{locale}", rt.handlers.DeleteVoteVariantTranslation)
 		})
 	})
 
 	r.Route("/golos/votes", func(r chi.Router) {
-		r.Post("/", rt.handlers.CreateVote)
-		r.Get("/", rt.handlers.GetUserVotes)
+		r.With(voting, rt.handlers.RequireAuth).Post("/", rt.handlers.CreateVote)
+		r.With(reads).Get("/", rt.handlers.GetUserVotes)
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", rt.handlers.GetVote)
-			r.Put("/", rt.handlers.DeleteVote)
-			r.Delete("/", rt.handlers.PatchVote)
+			r.With(reads).Get("/", rt.handlers.GetVote)
+			r.With(voting, rt.handlers.RequireAuth).Patch("/", rt.handlers.PatchVote)
+			r.With(voting, rt.handlers.RequireAuth).Delete("/", rt.handlers.DeleteVote)
+			r.With(reads).Get("/history", rt.handlers.GetVoteHistory)
 		})
 	})
 
+	r.Route("/golos/admin", func(r chi.Router) {
+		r.Use(writes)
+		r.With(rt.handlers.RequireRole(models.RoleAdmin)).Put("/users/{id}/role", rt.handlers.SetUserRole)
+		r.With(rt.handlers.RequireRole(models.RoleAdmin)).Post("/purge", rt.handlers.PurgeDeleted)
+
+		r.Group(func(r chi.Router) {
+			r.Use(rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin))
+			r.Post("/elections/{id}/close", rt.handlers.CloseElection)
+			r.Delete("/votes/{id}", rt.handlers.RemoveVote)
+		})
+	})
+
+	r.With(reads, rt.handlers.RequireRole(models.RoleModerator, models.RoleAdmin)).Get("/golos/audit", rt.handlers.GetAuditEvents)
+
+	// Пробы без лимитов, их дергают оркестратор и балансировщик
+	r.Get("/healthz", rt.handlers.Healthz)
+	r.Get("/readyz", rt.handlers.Readyz)
+
+	// Маршруты регистрируются только после всех r.Use, иначе chi паникует
+	if rt.metrics.Enabled {
+		r.Method(http.MethodGet, "/metrics", metrics.Handler())
+	}
+
 	return r
 }
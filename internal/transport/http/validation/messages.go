@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"strings"
+	"unicode"
+
+	apperrors "github.com/alonsoF100/golos/internal/erorrs"
+	"github.com/alonsoF100/golos/internal/i18n"
+	"github.com/go-playground/validator/v10"
+)
+
+// Правила, параметр которых - имя другого поля структуры
+var crossFieldRules = map[string]bool{
+	"nefield":   true,
+	"eqfield":   true,
+	"notbefore": true,
+}
+
+// Переводит ошибки валидатора в ошибки по полям на языке locale
+func FieldErrors(errs validator.ValidationErrors, locale string) []apperrors.FieldError {
+	fields := make([]apperrors.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		param := fe.Param()
+		if crossFieldRules[fe.Tag()] {
+			param = snakeCase(param)
+		}
+
+		args := map[string]string{"field": fe.Field(), "param": param}
+		key := "validation." + fe.Tag()
+		if _, ok := i18n.Message(locale, key); !ok {
+			key = "validation.default"
+		}
+		message := i18n.Format(locale, key, fe.Error(), args)
+
+		fields = append(fields, apperrors.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Param:   param,
+			Message: message,
+		})
+	}
+
+	return fields
+}
+
+// Имена полей Go в параметрах правил совпадают с json именами в snake_case
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Валидатор с именами полей из тегов json/query и собственными правилами
+func New() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(fieldName)
+
+	// Ошибка регистрации возможна только при пустом теге или nil функции
+	_ = v.RegisterValidation("notblank", notBlank)
+	_ = v.RegisterValidation("notbefore", notBefore)
+
+	return v
+}
+
+// Имя поля для клиента: json тег для тела, query тег для параметров запроса
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// Строка не должна состоять только из пробелов
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// notbefore=Field: дата RFC 3339 не раньше даты из поля Field той же структуры.
+// Пустые значения и ошибки формата проверяются другими правилами (datetime)
+func notBefore(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	other := fl.Parent().FieldByName(fl.Param())
+	if value == "" || !other.IsValid() || other.Kind() != reflect.String || other.String() == "" {
+		return true
+	}
+
+	to, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return true
+	}
+	from, err := time.Parse(time.RFC3339, other.String())
+	if err != nil {
+		return true
+	}
+
+	return !to.Before(from)
+}
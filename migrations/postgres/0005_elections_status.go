@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddElectionsStatus, downAddElectionsStatus)
+}
+
+func upAddElectionsStatus(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE elections
+			ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open';
+
+		CREATE INDEX idx_elections_status ON elections(status);
+		CREATE INDEX idx_elections_created_at ON elections(created_at);
+	`)
+	return err
+}
+
+func downAddElectionsStatus(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_elections_created_at;
+		DROP INDEX IF EXISTS idx_elections_status;
+		ALTER TABLE elections DROP COLUMN status;
+	`)
+	return err
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddElectionsSearch, downAddElectionsSearch)
+}
+
+// Конфигурация 'simple' выбрана специально: названия бывают и на русском, и на английском
+func upAddElectionsSearch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE elections
+			ADD COLUMN search_vector tsvector
+			GENERATED ALWAYS AS (
+				to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, ''))
+			) STORED;
+
+		CREATE INDEX idx_elections_search_vector ON elections USING GIN (search_vector);
+	`)
+	return err
+}
+
+func downAddElectionsSearch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_elections_search_vector;
+		ALTER TABLE elections DROP COLUMN search_vector;
+	`)
+	return err
+}
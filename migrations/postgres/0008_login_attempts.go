@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateLoginAttempts, downCreateLoginAttempts)
+}
+
+func upCreateLoginAttempts(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE login_attempts (
+			key VARCHAR(300) PRIMARY KEY,
+			failures INTEGER NOT NULL DEFAULT 0,
+			last_failure_at TIMESTAMP NOT NULL,
+			locked_until TIMESTAMP
+		);
+
+		CREATE TABLE login_lockouts (
+			id UUID PRIMARY KEY,
+			key VARCHAR(300) NOT NULL,
+			failures INTEGER NOT NULL,
+			locked_until TIMESTAMP NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_login_lockouts_key ON login_lockouts(key);
+		CREATE INDEX idx_login_lockouts_created_at ON login_lockouts(created_at);
+	`)
+	return err
+}
+
+func downCreateLoginAttempts(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE login_lockouts;
+		DROP TABLE login_attempts;
+	`)
+	return err
+}
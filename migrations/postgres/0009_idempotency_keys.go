@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateIdempotencyKeys, downCreateIdempotencyKeys)
+}
+
+// status_code = 0 означает, что запрос с этим ключом еще выполняется
+func upCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE idempotency_keys (
+			key VARCHAR(512) PRIMARY KEY,
+			request_hash VARCHAR(64) NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			body BYTEA,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+	`)
+	return err
+}
+
+func downCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE idempotency_keys;")
+	return err
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddVersions, downAddVersions)
+}
+
+func upAddVersions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+		ALTER TABLE elections ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+		ALTER TABLE vote_variants ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+	`)
+	return err
+}
+
+func downAddVersions(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE vote_variants DROP COLUMN version;
+		ALTER TABLE elections DROP COLUMN version;
+		ALTER TABLE users DROP COLUMN version;
+	`)
+	return err
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddSoftDelete, downAddSoftDelete)
+}
+
+// Уникальность nickname проверяется только среди неудаленных пользователей
+func upAddSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+		ALTER TABLE elections ADD COLUMN deleted_at TIMESTAMP;
+		ALTER TABLE vote_variants ADD COLUMN deleted_at TIMESTAMP;
+
+		ALTER TABLE users DROP CONSTRAINT users_nickname_key;
+		CREATE UNIQUE INDEX idx_users_nickname_active ON users(nickname) WHERE deleted_at IS NULL;
+
+		CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+		CREATE INDEX idx_elections_deleted_at ON elections(deleted_at) WHERE deleted_at IS NOT NULL;
+		CREATE INDEX idx_vote_variants_deleted_at ON vote_variants(deleted_at) WHERE deleted_at IS NOT NULL;
+	`)
+	return err
+}
+
+func downAddSoftDelete(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM vote_variants WHERE deleted_at IS NOT NULL;
+		DELETE FROM elections WHERE deleted_at IS NOT NULL;
+		DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+		DROP INDEX IF EXISTS idx_vote_variants_deleted_at;
+		DROP INDEX IF EXISTS idx_elections_deleted_at;
+		DROP INDEX IF EXISTS idx_users_deleted_at;
+
+		DROP INDEX IF EXISTS idx_users_nickname_active;
+		ALTER TABLE users ADD CONSTRAINT users_nickname_key UNIQUE (nickname);
+
+		ALTER TABLE vote_variants DROP COLUMN deleted_at;
+		ALTER TABLE elections DROP COLUMN deleted_at;
+		ALTER TABLE users DROP COLUMN deleted_at;
+	`)
+	return err
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateAuditEvents, downCreateAuditEvents)
+}
+
+// Журнал только дополняется: UPDATE и DELETE запрещены триггером.
+// actor_id без внешнего ключа, чтобы записи переживали окончательное удаление пользователя
+func upCreateAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE audit_events (
+			id UUID PRIMARY KEY,
+			actor_id UUID,
+			action VARCHAR(32) NOT NULL,
+			entity_type VARCHAR(32) NOT NULL,
+			entity_id VARCHAR(64) NOT NULL,
+			before JSONB,
+			after JSONB,
+			request_id VARCHAR(128),
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, created_at);
+		CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, created_at);
+		CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+		CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_events is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		CREATE TRIGGER audit_events_append_only
+		BEFORE UPDATE OR DELETE ON audit_events
+		FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+	`)
+	return err
+}
+
+func downCreateAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE audit_events;
+		DROP FUNCTION audit_events_append_only();
+	`)
+	return err
+}
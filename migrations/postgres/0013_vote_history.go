@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateVoteHistory, downCreateVoteHistory)
+}
+
+// Существующие выборы сохраняют прежнее поведение (голос можно менять).
+// История удаляется вместе с голосом, old_variant_id = NULL - первоначальный голос
+func upCreateVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE elections
+		ADD COLUMN vote_policy VARCHAR(32) NOT NULL DEFAULT 'changes_until_close'
+		CHECK (vote_policy IN ('final', 'changes_until_close'));
+
+		CREATE TABLE vote_history (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			vote_id UUID NOT NULL REFERENCES votes(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL,
+			old_variant_id UUID,
+			new_variant_id UUID NOT NULL,
+			changed_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX idx_vote_history_vote_id ON vote_history(vote_id, changed_at);
+
+		INSERT INTO vote_history (vote_id, user_id, new_variant_id, changed_at)
+		SELECT id, user_id, variant_id, COALESCE(created_at, NOW()) FROM votes;
+	`)
+	return err
+}
+
+func downCreateVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE vote_history;
+		ALTER TABLE elections DROP COLUMN vote_policy;
+	`)
+	return err
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateTranslations, downCreateTranslations)
+}
+
+// Переводы названий и описаний, основной текст остается в elections/vote_variants
+func upCreateTranslations(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE election_translations (
+			election_id UUID NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+			locale VARCHAR(8) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			description VARCHAR(512) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (election_id, locale)
+		);
+
+		CREATE TABLE vote_variant_translations (
+			vote_variant_id UUID NOT NULL REFERENCES vote_variants(id) ON DELETE CASCADE,
+			locale VARCHAR(8) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (vote_variant_id, locale)
+		);
+	`)
+	return err
+}
+
+func downCreateTranslations(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE vote_variant_translations;
+		DROP TABLE election_translations;
+	`)
+	return err
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddIdempotencyHeaders, downAddIdempotencyHeaders)
+}
+
+// Заголовки ответа, которые повтор должен вернуть вместе с телом
+func upAddIdempotencyHeaders(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE idempotency_keys
+			ADD COLUMN etag VARCHAR(64) NOT NULL DEFAULT '',
+			ADD COLUMN location VARCHAR(512) NOT NULL DEFAULT '';
+	`)
+	return err
+}
+
+func downAddIdempotencyHeaders(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE idempotency_keys
+			DROP COLUMN location,
+			DROP COLUMN etag;
+	`)
+	return err
+}
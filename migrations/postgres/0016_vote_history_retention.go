@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upKeepVoteHistory, downKeepVoteHistory)
+}
+
+// История переживает отзыв голоса: vote_id остается без внешнего ключа,
+// а сам отзыв записывается строкой с new_variant_id = NULL
+func upKeepVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE vote_history DROP CONSTRAINT vote_history_vote_id_fkey;
+		ALTER TABLE vote_history ALTER COLUMN new_variant_id DROP NOT NULL;
+	`)
+	return err
+}
+
+func downKeepVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM vote_history WHERE vote_id NOT IN (SELECT id FROM votes) OR new_variant_id IS NULL;
+		ALTER TABLE vote_history ALTER COLUMN new_variant_id SET NOT NULL;
+		ALTER TABLE vote_history
+			ADD CONSTRAINT vote_history_vote_id_fkey FOREIGN KEY (vote_id) REFERENCES votes(id) ON DELETE CASCADE;
+	`)
+	return err
+}
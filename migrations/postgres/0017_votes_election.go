@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddVotesElection, downAddVotesElection)
+}
+
+// Один голос пользователя на выборы. Прежняя схема разрешала несколько голосов
+// в одних выборах: остается последний поданный, остальные отзываются с записью в vote_history
+func upAddVotesElection(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE votes
+			ADD COLUMN election_id UUID REFERENCES elections(id) ON DELETE CASCADE;
+
+		UPDATE votes v SET election_id = vv.election_id
+		FROM vote_variants vv
+		WHERE vv.id = v.variant_id;
+
+		ALTER TABLE votes ALTER COLUMN election_id SET NOT NULL;
+
+		WITH ranked AS (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY user_id, election_id
+				ORDER BY created_at DESC NULLS LAST, id DESC
+			) AS position
+			FROM votes
+		), dropped AS (
+			DELETE FROM votes v
+			USING ranked r
+			WHERE v.id = r.id AND r.position > 1
+			RETURNING v.id, v.user_id, v.variant_id
+		)
+		INSERT INTO vote_history (vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+		SELECT id, user_id, variant_id, NULL, NOW() FROM dropped;
+
+		CREATE UNIQUE INDEX idx_votes_user_election ON votes(user_id, election_id);
+	`)
+	return err
+}
+
+// Отозванные дубликаты не возвращаются: в истории остается запись об отзыве
+func downAddVotesElection(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_votes_user_election;
+		ALTER TABLE votes DROP COLUMN election_id;
+	`)
+	return err
+}
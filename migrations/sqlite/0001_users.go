@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(1, upCreateUsers, downCreateUsers)
+}
+
+// Схема сразу в текущем виде migrations/postgres: версии, мягкое удаление,
+// уникальность nickname только среди неудаленных
+func upCreateUsers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			nickname TEXT NOT NULL,
+			password TEXT NOT NULL,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			version INTEGER NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMP
+		);
+		CREATE UNIQUE INDEX idx_users_nickname_active ON users(nickname) WHERE deleted_at IS NULL;
+		CREATE INDEX idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+	`)
+	return err
+}
+
+func downCreateUsers(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE users;")
+	return err
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(2, upCreateElections, downCreateElections)
+}
+
+// Вместо tsvector поиск идет через функцию golos_search_rank, см. internal/repository/database/sqlite
+func upCreateElections(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE elections (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			description TEXT,
+			status TEXT NOT NULL DEFAULT 'open',
+			vote_policy TEXT NOT NULL DEFAULT 'changes_until_close'
+				CHECK (vote_policy IN ('final', 'changes_until_close')),
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			version INTEGER NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMP
+		);
+		CREATE INDEX idx_elections_user_id ON elections(user_id);
+		CREATE INDEX idx_elections_name ON elections(name);
+		CREATE INDEX idx_elections_status ON elections(status);
+		CREATE INDEX idx_elections_created_at ON elections(created_at);
+		CREATE INDEX idx_elections_deleted_at ON elections(deleted_at) WHERE deleted_at IS NOT NULL;
+	`)
+	return err
+}
+
+func downCreateElections(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE elections;")
+	return err
+}
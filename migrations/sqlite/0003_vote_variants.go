@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(3, upCreateVoteVariants, downCreateVoteVariants)
+}
+
+func upCreateVoteVariants(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE vote_variants (
+			id TEXT PRIMARY KEY,
+			election_id TEXT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			version INTEGER NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMP
+		);
+		CREATE INDEX idx_vote_variants_election_id ON vote_variants(election_id);
+		CREATE INDEX idx_vote_variants_deleted_at ON vote_variants(deleted_at) WHERE deleted_at IS NOT NULL;
+	`)
+	return err
+}
+
+func downCreateVoteVariants(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE vote_variants;")
+	return err
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(4, upCreateVotes, downCreateVotes)
+}
+
+// old_variant_id = NULL - первоначальный голос, id истории генерирует приложение
+func upCreateVotes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE votes (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			variant_id TEXT NOT NULL REFERENCES vote_variants(id) ON DELETE CASCADE,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			UNIQUE(user_id, variant_id)
+		);
+		CREATE INDEX idx_votes_user_id ON votes(user_id);
+		CREATE INDEX idx_votes_variant_id ON votes(variant_id);
+		CREATE TABLE vote_history (
+			id TEXT PRIMARY KEY,
+			vote_id TEXT NOT NULL REFERENCES votes(id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			old_variant_id TEXT,
+			new_variant_id TEXT NOT NULL,
+			changed_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX idx_vote_history_vote_id ON vote_history(vote_id, changed_at);
+	`)
+	return err
+}
+
+func downCreateVotes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE vote_history;
+		DROP TABLE votes;
+	`)
+	return err
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(5, upCreateUserRoles, downCreateUserRoles)
+}
+
+// Отсутствие строки означает обычного пользователя (role = 'user')
+func upCreateUserRoles(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE user_roles (
+			user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			role TEXT NOT NULL CHECK (role IN ('user', 'moderator', 'admin')),
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP
+		);
+		CREATE INDEX idx_user_roles_role ON user_roles(role);
+	`)
+	return err
+}
+
+func downCreateUserRoles(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE user_roles;")
+	return err
+}
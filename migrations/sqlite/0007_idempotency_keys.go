@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(7, upCreateIdempotencyKeys, downCreateIdempotencyKeys)
+}
+
+// status_code = 0 означает, что запрос с этим ключом еще выполняется
+func upCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE idempotency_keys (
+			key TEXT PRIMARY KEY,
+			request_hash TEXT NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			body BLOB,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+	`)
+	return err
+}
+
+func downCreateIdempotencyKeys(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE idempotency_keys;")
+	return err
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(8, upCreateAuditEvents, downCreateAuditEvents)
+}
+
+// Журнал только дополняется: UPDATE и DELETE запрещены триггерами
+func upCreateAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE audit_events (
+			id TEXT PRIMARY KEY,
+			actor_id TEXT,
+			action TEXT NOT NULL,
+			entity_type TEXT NOT NULL,
+			entity_id TEXT NOT NULL,
+			before BLOB,
+			after BLOB,
+			request_id TEXT,
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, created_at);
+		CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, created_at);
+		CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+		CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events
+		BEGIN
+			SELECT RAISE(ABORT, 'audit_events is append-only');
+		END;
+		CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events
+		BEGIN
+			SELECT RAISE(ABORT, 'audit_events is append-only');
+		END;
+	`)
+	return err
+}
+
+func downCreateAuditEvents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DROP TABLE audit_events;")
+	return err
+}
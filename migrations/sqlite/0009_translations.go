@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(9, upCreateTranslations, downCreateTranslations)
+}
+
+func upCreateTranslations(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE election_translations (
+			election_id TEXT NOT NULL REFERENCES elections(id) ON DELETE CASCADE,
+			locale TEXT NOT NULL,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (election_id, locale)
+		);
+		CREATE TABLE vote_variant_translations (
+			vote_variant_id TEXT NOT NULL REFERENCES vote_variants(id) ON DELETE CASCADE,
+			locale TEXT NOT NULL,
+			name TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (vote_variant_id, locale)
+		);
+	`)
+	return err
+}
+
+func downCreateTranslations(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP TABLE vote_variant_translations;
+		DROP TABLE election_translations;
+	`)
+	return err
+}
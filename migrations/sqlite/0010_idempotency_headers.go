@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(10, upAddIdempotencyHeaders, downAddIdempotencyHeaders)
+}
+
+// Заголовки ответа, которые повтор должен вернуть вместе с телом
+func upAddIdempotencyHeaders(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE idempotency_keys ADD COLUMN etag TEXT NOT NULL DEFAULT '';
+		ALTER TABLE idempotency_keys ADD COLUMN location TEXT NOT NULL DEFAULT '';
+	`)
+	return err
+}
+
+func downAddIdempotencyHeaders(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE idempotency_keys DROP COLUMN location;
+		ALTER TABLE idempotency_keys DROP COLUMN etag;
+	`)
+	return err
+}
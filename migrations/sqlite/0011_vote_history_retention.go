@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+func init() {
+	register(11, upKeepVoteHistory, downKeepVoteHistory)
+}
+
+// История переживает отзыв голоса: vote_id остается без внешнего ключа,
+// а сам отзыв записывается строкой с new_variant_id = NULL.
+// sqlite не умеет удалять ограничения, поэтому таблица пересоздается
+func upKeepVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE vote_history_new (
+			id TEXT PRIMARY KEY,
+			vote_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			old_variant_id TEXT,
+			new_variant_id TEXT,
+			changed_at TIMESTAMP NOT NULL
+		);
+		INSERT INTO vote_history_new SELECT id, vote_id, user_id, old_variant_id, new_variant_id, changed_at FROM vote_history;
+		DROP TABLE vote_history;
+		ALTER TABLE vote_history_new RENAME TO vote_history;
+		CREATE INDEX idx_vote_history_vote_id ON vote_history(vote_id, changed_at);
+	`)
+	return err
+}
+
+func downKeepVoteHistory(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		CREATE TABLE vote_history_old (
+			id TEXT PRIMARY KEY,
+			vote_id TEXT NOT NULL REFERENCES votes(id) ON DELETE CASCADE,
+			user_id TEXT NOT NULL,
+			old_variant_id TEXT,
+			new_variant_id TEXT NOT NULL,
+			changed_at TIMESTAMP NOT NULL
+		);
+		INSERT INTO vote_history_old
+		SELECT id, vote_id, user_id, old_variant_id, new_variant_id, changed_at FROM vote_history
+		WHERE vote_id IN (SELECT id FROM votes) AND new_variant_id IS NOT NULL;
+		DROP TABLE vote_history;
+		ALTER TABLE vote_history_old RENAME TO vote_history;
+		CREATE INDEX idx_vote_history_vote_id ON vote_history(vote_id, changed_at);
+	`)
+	return err
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	register(12, upAddVotesElection, downAddVotesElection)
+}
+
+// Формат времени репозитория sqlite: строка фиксированной ширины в UTC
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+// Один голос пользователя на выборы. ADD COLUMN в sqlite не добавляет NOT NULL,
+// election_id заполняет приложение при каждой вставке и смене варианта.
+// Прежняя схема разрешала несколько голосов в одних выборах: остается последний
+// поданный, остальные отзываются с записью в vote_history
+func upAddVotesElection(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		ALTER TABLE votes ADD COLUMN election_id TEXT REFERENCES elections(id) ON DELETE CASCADE;
+		UPDATE votes SET election_id = (SELECT election_id FROM vote_variants WHERE id = votes.variant_id);
+	`)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, user_id, variant_id FROM (
+			SELECT id, user_id, variant_id, ROW_NUMBER() OVER (
+				PARTITION BY user_id, election_id
+				ORDER BY created_at DESC NULLS LAST, id DESC
+			) AS position
+			FROM votes
+		)
+		WHERE position > 1
+	`)
+	if err != nil {
+		return err
+	}
+
+	type duplicate struct {
+		id, userID, variantID string
+	}
+	var duplicates []duplicate
+	for rows.Next() {
+		var d duplicate
+		if err := rows.Scan(&d.id, &d.userID, &d.variantID); err != nil {
+			rows.Close()
+			return err
+		}
+		duplicates = append(duplicates, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	changedAt := time.Now().UTC().Format(timeLayout)
+	for _, d := range duplicates {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO vote_history (id, vote_id, user_id, old_variant_id, new_variant_id, changed_at)
+			VALUES (?, ?, ?, ?, NULL, ?)`,
+			uuid.NewString(), d.id, d.userID, d.variantID, changedAt)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM votes WHERE id = ?`, d.id); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE UNIQUE INDEX idx_votes_user_election ON votes(user_id, election_id);
+	`)
+	return err
+}
+
+// Отозванные дубликаты не возвращаются: в истории остается запись об отзыве
+func downAddVotesElection(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		DROP INDEX IF EXISTS idx_votes_user_election;
+		ALTER TABLE votes DROP COLUMN election_id;
+	`)
+	return err
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Миграции sqlite не попадают в глобальный реестр goose, иначе их версии
+// столкнулись бы с migrations/postgres в одном бинарнике
+var registered []*goose.Migration
+
+func register(version int64, up, down func(context.Context, *sql.Tx) error) {
+	registered = append(registered, goose.NewGoMigration(version, &goose.GoFunc{RunTx: up}, &goose.GoFunc{RunTx: down}))
+}
+
+func Migrations() []*goose.Migration {
+	return registered
+}